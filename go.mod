@@ -1,46 +1,52 @@
 module github.com/wpdirectory/wpdir
 
+go 1.27.1
+
+require (
+	github.com/boltdb/bolt v1.3.1
+	github.com/go-chi/chi v3.3.3+incompatible
+	github.com/go-chi/cors v1.0.0
+	github.com/gogo/protobuf v1.1.1
+	github.com/oklog/ulid v0.3.0
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/prometheus/client_golang v0.8.0
+	github.com/robfig/cron v0.0.0-20180505203441-b41be1df6967
+	github.com/spf13/viper v1.0.2
+	github.com/ulule/limiter v2.2.0+incompatible
+	github.com/wcharczuk/go-chart v0.0.0-20180415235301-9e3a080aa3e7
+	github.com/wpdirectory/wporg v0.0.0-20190512111825-d61ae0bb2684
+	golang.org/x/crypto v0.0.0-20180910181607-0e37d006457b
+)
+
 require (
 	github.com/BurntSushi/toml v0.3.0 // indirect
 	github.com/beorn7/perks v0.0.0-20180321164747-3a771d992973 // indirect
 	github.com/blend/go-sdk v0.0.0-20180909172529-8fb98a598f74 // indirect
-	github.com/boltdb/bolt v1.3.1
 	github.com/davecgh/go-spew v1.1.0 // indirect
 	github.com/fsnotify/fsnotify v1.4.7 // indirect
-	github.com/go-chi/chi v3.3.3+incompatible
-	github.com/go-chi/cors v1.0.0
-	github.com/gogo/protobuf v1.1.1
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
 	github.com/golang/protobuf v1.1.0 // indirect
 	github.com/hashicorp/hcl v0.0.0-20180404174102-ef8a98b0bbce // indirect
 	github.com/magiconair/properties v1.8.0 // indirect
 	github.com/matttproud/golang_protobuf_extensions v1.0.1 // indirect
 	github.com/mitchellh/mapstructure v0.0.0-20180511142126-bb74f1db0675 // indirect
-	github.com/oklog/ulid v0.3.0
 	github.com/pelletier/go-toml v1.1.0 // indirect
 	github.com/pkg/errors v0.8.0 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/prometheus/client_golang v0.8.0
 	github.com/prometheus/client_model v0.0.0-20180712105110-5c3871d89910 // indirect
 	github.com/prometheus/common v0.0.0-20180801064454-c7de2306084e // indirect
 	github.com/prometheus/procfs v0.0.0-20180725123919-05ee40e3a273 // indirect
-	github.com/robfig/cron v0.0.0-20180505203441-b41be1df6967
 	github.com/shurcooL/httpfs v0.0.0-20171119174359-809beceb2371 // indirect
 	github.com/shurcooL/vfsgen v0.0.0-20180909233225-12c1e538e8c6 // indirect
 	github.com/spf13/afero v1.1.0 // indirect
 	github.com/spf13/cast v1.2.0 // indirect
 	github.com/spf13/jwalterweatherman v0.0.0-20180109140146-7c0cea34c8ec // indirect
 	github.com/spf13/pflag v1.0.1 // indirect
-	github.com/spf13/viper v1.0.2
 	github.com/stretchr/testify v1.2.2 // indirect
-	github.com/ulule/limiter v2.2.0+incompatible
-	github.com/wcharczuk/go-chart v0.0.0-20180415235301-9e3a080aa3e7
-	github.com/wpdirectory/wporg v0.0.0-20190512111825-d61ae0bb2684
-	golang.org/x/crypto v0.0.0-20180910181607-0e37d006457b
 	golang.org/x/image v0.0.0-20180708004352-c73c2afc3b81 // indirect
 	golang.org/x/net v0.0.0-20180530234432-1e491301e022 // indirect
 	golang.org/x/sync v0.0.0-20180314180146-1d60e4601c6f // indirect
 	golang.org/x/sys v0.0.0-20180907202204-917fdcba135d // indirect
 	golang.org/x/text v0.3.0 // indirect
+	gopkg.in/check.v1 v0.0.0-20161208181325-20d25e280405 // indirect
 	gopkg.in/yaml.v2 v2.2.1 // indirect
 )
@@ -156,6 +156,11 @@ func (ix *Index) Paths() []string {
 	return x
 }
 
+// NumNames returns the number of indexed file names.
+func (ix *Index) NumNames() int {
+	return ix.numName
+}
+
 // NameBytes returns the name corresponding to the given fileid.
 func (ix *Index) NameBytes(fileid uint32) []byte {
 	off := ix.uint32(ix.nameIndex + 4*fileid)
@@ -47,6 +47,16 @@ func (q *Query) or(r *Query) *Query {
 	return q.andOr(r, QOr)
 }
 
+// And returns the query q AND r, possibly reusing q's and r's storage.
+func (q *Query) And(r *Query) *Query {
+	return q.and(r)
+}
+
+// Or returns the query q OR r, possibly reusing q's and r's storage.
+func (q *Query) Or(r *Query) *Query {
+	return q.or(r)
+}
+
 // andOr returns the query q AND r or q OR r, possibly reusing q's and r's storage.
 // It works hard to avoid creating unnecessarily complicated structures.
 func (q *Query) andOr(r *Query, op QueryOp) (out *Query) {
@@ -1,31 +1,92 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"regexp"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config contains global application information
 type Config struct {
-	Version       string
-	Name          string
-	Commit        string
-	Date          string
-	WD            string
-	UpdateWorkers int
-	SearchWorkers int
-	Host          string
-	Domains       string
-	Standalone    bool
-	DevMode       bool
-	Ports         struct {
+	Version            string
+	Name               string
+	Commit             string
+	Date               string
+	WD                 string
+	UpdateWorkers      int
+	SearchWorkers      int
+	ConcurrentSearches int
+	SearchTimeout      time.Duration
+	MatchLineLength    int
+	IndexVersions      int
+	Host               string
+	Domains            string
+	Standalone         bool
+	DevMode            bool
+	Ports              struct {
 		HTTP  string
 		HTTPS string
 	}
+	Users        []User
+	Webhooks     []Webhook
+	Repositories []Repository
 }
 
+// User contains the credentials of an admin user
+type User struct {
+	Username string
+	Password string
+}
+
+// Webhook contains an endpoint notified of events
+// Events lists the event types sent, all events are sent if it is empty.
+type Webhook struct {
+	URL    string
+	Secret string
+	Events []string
+}
+
+// Repository defines a named Repo of Extensions
+// Type is the kind of Extension held, plugins or themes. It defaults to the
+// Name when that is plugins or themes and to plugins otherwise.
+type Repository struct {
+	Name   string
+	Type   string
+	Source Source
+}
+
+// Source sets where the Extensions of a Repo are fetched from
+// Type is wporg, local, svn or git. Dir is the directory read by local
+// Sources. URL is the root of an SVN repository holding a directory per
+// Extension, or a Git URL in which {slug} is replaced by the Extension slug.
+// Ref is the SVN path or Git branch or tag checked out, by default trunk and
+// the remote HEAD. Slugs limits the Extensions of an SVN Source, Git
+// Sources only hold the Slugs listed.
+type Source struct {
+	Type  string
+	Dir   string
+	URL   string
+	Ref   string
+	Slugs []string
+}
+
+var (
+	// defaultRepositories are used when none are set in the config
+	defaultRepositories = []Repository{
+		{Name: "plugins", Type: "plugins"},
+		{Name: "themes", Type: "themes"},
+	}
+	// reservedNames may not name a Repository, they are used by searches
+	// targeting every Repository and by the DB, which also refuses to
+	// create a Repository bucket named after one of its own
+	reservedNames = []string{"all", "repos", "searches", "charts", "watches", "webhooks", "updates"}
+	validName     = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+)
+
 // Setup creates, fills and returns the Config struct
 func Setup(version, commit, date string, dev bool) *Config {
 	viper.SetDefault("name", "wpdirectory")
@@ -33,6 +94,10 @@ func Setup(version, commit, date string, dev bool) *Config {
 	viper.SetDefault("date", "")
 	viper.SetDefault("updateworkers", 4)
 	viper.SetDefault("searchworkers", 6)
+	viper.SetDefault("concurrentsearches", 2)
+	viper.SetDefault("searchtimeout", "5m")
+	viper.SetDefault("matchlinelength", 500)
+	viper.SetDefault("indexversions", 1)
 	viper.SetDefault("host", "http://localhost")
 	viper.SetDefault("domains", "wpdirectory.net,www.wpdirectory.net")
 	viper.SetDefault("standalone", false)
@@ -56,21 +121,79 @@ func Setup(version, commit, date string, dev bool) *Config {
 	}
 
 	config := &Config{
-		Version:       version,
-		Name:          viper.GetString("name"),
-		Commit:        commit,
-		Date:          date,
-		WD:            wd,
-		UpdateWorkers: viper.GetInt("updateworkers"),
-		SearchWorkers: viper.GetInt("searchworkers"),
-		Host:          viper.GetString("host"),
-		Domains:       viper.GetString("domains"),
-		Standalone:    viper.GetBool("standalone"),
-		DevMode:       viper.GetBool("dev"),
+		Version:            version,
+		Name:               viper.GetString("name"),
+		Commit:             commit,
+		Date:               date,
+		WD:                 wd,
+		UpdateWorkers:      viper.GetInt("updateworkers"),
+		SearchWorkers:      viper.GetInt("searchworkers"),
+		ConcurrentSearches: viper.GetInt("concurrentsearches"),
+		SearchTimeout:      viper.GetDuration("searchtimeout"),
+		MatchLineLength:    viper.GetInt("matchlinelength"),
+		IndexVersions:      viper.GetInt("indexversions"),
+		Host:               viper.GetString("host"),
+		Domains:            viper.GetString("domains"),
+		Standalone:         viper.GetBool("standalone"),
+		DevMode:            viper.GetBool("dev"),
 	}
 
 	config.Ports.HTTP = viper.GetString("ports.http")
 	config.Ports.HTTPS = viper.GetString("ports.https")
 
+	err = viper.UnmarshalKey("users", &config.Users)
+	if err != nil {
+		log.Printf("Error reading users from config: %s\n", err)
+	}
+
+	err = viper.UnmarshalKey("webhooks", &config.Webhooks)
+	if err != nil {
+		log.Printf("Error reading webhooks from config: %s\n", err)
+	}
+
+	err = viper.UnmarshalKey("repositories", &config.Repositories)
+	if err != nil {
+		log.Printf("Error reading repositories from config: %s\n", err)
+	}
+	if len(config.Repositories) == 0 {
+		config.Repositories = defaultRepositories
+	}
+	for i := range config.Repositories {
+		err = setupRepository(&config.Repositories[i], config.Repositories[:i])
+		if err != nil {
+			log.Fatalf("Error in repositories config: %s\n", err)
+		}
+	}
+
 	return config
 }
+
+// setupRepository checks a Repository is valid and not a duplicate of
+// those before it, setting its default Type
+func setupRepository(r *Repository, before []Repository) error {
+	if !validName.MatchString(r.Name) {
+		return fmt.Errorf("invalid repository name: %q", r.Name)
+	}
+	for _, name := range reservedNames {
+		if r.Name == name {
+			return fmt.Errorf("repository name is reserved: %s", r.Name)
+		}
+	}
+	for _, b := range before {
+		if r.Name == b.Name {
+			return fmt.Errorf("duplicate repository name: %s", r.Name)
+		}
+	}
+
+	if r.Type == "" {
+		r.Type = "plugins"
+		if r.Name == "themes" {
+			r.Type = "themes"
+		}
+	}
+	if r.Type != "plugins" && r.Type != "themes" {
+		return fmt.Errorf("repository %s has invalid type: %s", r.Name, r.Type)
+	}
+
+	return nil
+}
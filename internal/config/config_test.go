@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestSetupRepository(t *testing.T) {
+	before := []Repository{
+		{Name: "plugins", Type: "plugins"},
+		{Name: "themes", Type: "themes"},
+	}
+
+	tests := []struct {
+		repo  Repository
+		typ   string
+		valid bool
+	}{
+		{Repository{Name: "internal"}, "plugins", true},
+		{Repository{Name: "client-themes", Type: "themes"}, "themes", true},
+		{Repository{Name: "mu_plugins2", Type: "plugins"}, "plugins", true},
+		{Repository{Name: ""}, "", false},
+		{Repository{Name: "Internal"}, "", false},
+		{Repository{Name: "-internal"}, "", false},
+		{Repository{Name: "my repo"}, "", false},
+		{Repository{Name: "a/b"}, "", false},
+		{Repository{Name: "all"}, "", false},
+		{Repository{Name: "searches"}, "", false},
+		{Repository{Name: "updates"}, "", false},
+		{Repository{Name: "plugins"}, "", false},
+		{Repository{Name: "internal", Type: "widgets"}, "", false},
+	}
+
+	for _, test := range tests {
+		r := test.repo
+		err := setupRepository(&r, before)
+		if (err == nil) != test.valid {
+			t.Errorf("Expected valid %t got error %v for %+v", test.valid, err, test.repo)
+			continue
+		}
+		if test.valid && r.Type != test.typ {
+			t.Errorf("Expected %+v got %+v for %+v", test.typ, r.Type, test.repo)
+		}
+	}
+}
+
+func TestSetupRepositoryDefaults(t *testing.T) {
+	repos := []Repository{{Name: "plugins"}, {Name: "themes"}}
+	for i := range repos {
+		if err := setupRepository(&repos[i], repos[:i]); err != nil {
+			t.Fatalf("Unexpected error %s for %+v", err, repos[i])
+		}
+	}
+
+	if repos[0].Type != "plugins" || repos[1].Type != "themes" {
+		t.Errorf("Expected plugins and themes types got %+v", repos)
+	}
+}
+
+func TestReservedNames(t *testing.T) {
+	reserved := make(map[string]bool)
+	for _, name := range reservedNames {
+		reserved[name] = true
+	}
+
+	for _, name := range []string{"all", "repos", "searches", "charts", "watches", "webhooks", "updates"} {
+		if !reserved[name] {
+			t.Errorf("Expected %s to be reserved", name)
+		}
+	}
+}
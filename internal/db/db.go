@@ -1,6 +1,7 @@
 package db
 
 import (
+	"bytes"
 	"errors"
 	"log"
 	"path/filepath"
@@ -13,16 +14,32 @@ var (
 	db      *bolt.DB
 	buckets = []string{
 		"repos",
-		"plugins",
-		"themes",
 		"searches",
 		"charts",
+		"watches",
+		"webhooks",
+		"updates",
 	}
 	searchBuckets = []string{
 		"search_data",
 		"all_dates",
 		"public_dates",
 	}
+	watchBuckets = []string{
+		"watch_data",
+		"watch_changes",
+		"watch_events",
+	}
+	updateBuckets = []string{
+		"update_pending",
+		"update_dead",
+	}
+	// nestedBuckets lists the internal buckets of main buckets
+	nestedBuckets = map[string][]string{
+		"searches": searchBuckets,
+		"watches":  watchBuckets,
+		"updates":  updateBuckets,
+	}
 )
 
 // Close closes the bolt db.
@@ -54,12 +71,10 @@ func Setup(dir string) {
 			if err != nil {
 				return err
 			}
-			if name == "searches" {
-				for _, name := range searchBuckets {
-					_, err := b.CreateBucketIfNotExists([]byte(name))
-					if err != nil {
-						return err
-					}
+			for _, name := range nestedBuckets[name] {
+				_, err := b.CreateBucketIfNotExists([]byte(name))
+				if err != nil {
+					return err
 				}
 			}
 		}
@@ -70,6 +85,21 @@ func Setup(dir string) {
 	}
 }
 
+// CreateBucket ensures a bucket exists
+// Used for the buckets holding the Extensions of each Repo, names of the
+// main buckets are rejected.
+func CreateBucket(name string) error {
+	for _, b := range buckets {
+		if name == b {
+			return errors.New("Bucket name is reserved")
+		}
+	}
+	return db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(name))
+		return err
+	})
+}
+
 // PutToBucket adds an item to bucket
 func PutToBucket(key string, content []byte, bucket string) error {
 	err := db.Update(func(tx *bolt.Tx) error {
@@ -241,6 +271,33 @@ func GetSummary(searchID string) ([]byte, error) {
 	return data, err
 }
 
+// SaveResultOrder saves the order in which a Search found its Results
+func SaveResultOrder(searchID string, bytes []byte) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		s := tx.Bucket([]byte("searches"))
+		data := s.Bucket([]byte("search_data"))
+
+		return data.Put([]byte(searchID+"_order"), bytes)
+	})
+}
+
+// GetResultOrder gets the order in which a Search found its Results
+func GetResultOrder(searchID string) ([]byte, error) {
+	var data []byte
+	err := db.View(func(tx *bolt.Tx) error {
+		s := tx.Bucket([]byte("searches"))
+		sd := s.Bucket([]byte("search_data"))
+		if v := sd.Get([]byte(searchID + "_order")); v != nil {
+			data = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	if err == nil && len(data) == 0 {
+		err = errors.New("No data found")
+	}
+	return data, err
+}
+
 // SaveMatches saves the Search Matches to DB
 func SaveMatches(searchID string, list map[string][]byte) error {
 	// Start a writable transaction.
@@ -283,3 +340,220 @@ func GetMatches(searchID string, slug string) ([]byte, error) {
 	}
 	return data, err
 }
+
+// ForEachMatches calls fn with the slug and Matches data of every Extension
+// in a Search, in slug order. Each Extension's data is copied out of the DB
+// before fn is called, so no transaction is held open while fn runs.
+func ForEachMatches(searchID string, fn func(slug string, data []byte) error) error {
+	prefix := []byte(searchID + "_matches_")
+
+	var keys [][]byte
+	err := db.View(func(tx *bolt.Tx) error {
+		s := tx.Bucket([]byte("searches"))
+		c := s.Bucket([]byte("search_data")).Cursor()
+
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			keys = append(keys, append([]byte(nil), k...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		var data []byte
+		err := db.View(func(tx *bolt.Tx) error {
+			s := tx.Bucket([]byte("searches"))
+			if v := s.Bucket([]byte("search_data")).Get(k); v != nil {
+				data = append([]byte(nil), v...)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			continue
+		}
+		if err := fn(string(k[len(prefix):]), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveWatch saves the Watch data to DB
+func SaveWatch(watchID string, bytes []byte) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		w := tx.Bucket([]byte("watches"))
+		return w.Bucket([]byte("watch_data")).Put([]byte(watchID), bytes)
+	})
+}
+
+// GetWatch gets Watch data by ID
+func GetWatch(watchID string) ([]byte, error) {
+	var data []byte
+	err := db.View(func(tx *bolt.Tx) error {
+		w := tx.Bucket([]byte("watches"))
+		if v := w.Bucket([]byte("watch_data")).Get([]byte(watchID)); v != nil {
+			data = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	if err == nil && len(data) == 0 {
+		err = errors.New("No data found")
+	}
+	return data, err
+}
+
+// GetWatches returns the data of every Watch
+func GetWatches() ([][]byte, error) {
+	var list [][]byte
+	err := db.View(func(tx *bolt.Tx) error {
+		w := tx.Bucket([]byte("watches"))
+		return w.Bucket([]byte("watch_data")).ForEach(func(k, v []byte) error {
+			list = append(list, append([]byte(nil), v...))
+			return nil
+		})
+	})
+	return list, err
+}
+
+// DeleteWatch removes a Watch along with the changes and events found by it
+func DeleteWatch(watchID string) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		w := tx.Bucket([]byte("watches"))
+		if err := w.Bucket([]byte("watch_data")).Delete([]byte(watchID)); err != nil {
+			return err
+		}
+
+		prefix := []byte(watchID + "_")
+		for _, name := range []string{"watch_changes", "watch_events"} {
+			c := w.Bucket([]byte(name)).Cursor()
+			for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Seek(prefix) {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// SaveWatchChanges saves the changes found by a Watch run
+// Keys are ordered by Search ID, so changes are kept in the order found.
+func SaveWatchChanges(watchID string, searchID string, bytes []byte) error {
+	return putWatchItem("watch_changes", watchID, searchID, bytes)
+}
+
+// GetWatchChanges returns the changes found by a Watch, newest first
+func GetWatchChanges(watchID string) ([][]byte, error) {
+	return getWatchItems("watch_changes", watchID)
+}
+
+// SaveWatchEvent saves a Watch event, eventID should be a ULID so events
+// are kept in the order found
+func SaveWatchEvent(watchID string, eventID string, bytes []byte) error {
+	return putWatchItem("watch_events", watchID, eventID, bytes)
+}
+
+// GetWatchEvents returns the events recorded for a Watch, newest first
+func GetWatchEvents(watchID string) ([][]byte, error) {
+	return getWatchItems("watch_events", watchID)
+}
+
+// putWatchItem stores data belonging to a Watch in an internal bucket
+func putWatchItem(bucket string, watchID string, ID string, bytes []byte) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		w := tx.Bucket([]byte("watches"))
+		return w.Bucket([]byte(bucket)).Put([]byte(watchID+"_"+ID), bytes)
+	})
+}
+
+// getWatchItems returns the data belonging to a Watch in an internal bucket,
+// newest first
+func getWatchItems(bucket string, watchID string) ([][]byte, error) {
+	var list [][]byte
+	err := db.View(func(tx *bolt.Tx) error {
+		w := tx.Bucket([]byte("watches"))
+		c := w.Bucket([]byte(bucket)).Cursor()
+
+		prefix := []byte(watchID + "_")
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			list = append([][]byte{append([]byte(nil), v...)}, list...)
+		}
+		return nil
+	})
+	return list, err
+}
+
+// SaveDelivery saves a webhook delivery record
+func SaveDelivery(deliveryID string, bytes []byte) error {
+	return PutToBucket(deliveryID, bytes, "webhooks")
+}
+
+// GetDeliveries returns up to limit webhook delivery records, newest first
+func GetDeliveries(limit int) ([][]byte, error) {
+	var list [][]byte
+	err := db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket([]byte("webhooks")).Cursor()
+		for k, v := c.Last(); k != nil && len(list) < limit; k, v = c.Prev() {
+			list = append(list, append([]byte(nil), v...))
+		}
+		return nil
+	})
+	return list, err
+}
+
+// SaveUpdates saves queued updates in an internal bucket of the updates
+// bucket, update_pending or update_dead, in a single transaction
+func SaveUpdates(bucket string, list map[string][]byte) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		u := tx.Bucket([]byte("updates")).Bucket([]byte(bucket))
+		for key, bytes := range list {
+			if err := u.Put([]byte(key), bytes); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteUpdates deletes queued updates from an internal bucket
+func DeleteUpdates(bucket string, keys []string) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		u := tx.Bucket([]byte("updates")).Bucket([]byte(bucket))
+		for _, key := range keys {
+			if err := u.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MoveUpdate deletes a queued update from one internal bucket and saves it
+// in another at once
+func MoveUpdate(from string, to string, key string, bytes []byte) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		u := tx.Bucket([]byte("updates"))
+		if err := u.Bucket([]byte(from)).Delete([]byte(key)); err != nil {
+			return err
+		}
+		return u.Bucket([]byte(to)).Put([]byte(key), bytes)
+	})
+}
+
+// GetUpdates returns every queued update in an internal bucket
+func GetUpdates(bucket string) ([][]byte, error) {
+	var list [][]byte
+	err := db.View(func(tx *bolt.Tx) error {
+		u := tx.Bucket([]byte("updates"))
+		return u.Bucket([]byte(bucket)).ForEach(func(k, v []byte) error {
+			list = append(list, append([]byte(nil), v...))
+			return nil
+		})
+	})
+	return list, err
+}
@@ -18,7 +18,10 @@ type Stats struct {
 
 // File contains basic data about a specific file
 type File struct {
-	Name      string `json:"name"`
+	Name string `json:"name"`
+	// Path is the full name within the archive, it is empty in Stats
+	// recorded before paths were kept
+	Path      string `json:"path,omitempty"`
 	Extension string `json:"extension"`
 	Size      int64  `json:"size"`
 }
@@ -47,6 +50,7 @@ func (s *Stats) AddFile(zf *zip.File) {
 	defer s.RUnlock()
 	file := File{
 		Name:      f.Name(),
+		Path:      zf.Name,
 		Extension: filepath.Ext(f.Name()),
 		Size:      f.Size(),
 	}
@@ -44,7 +44,7 @@ func TestAddFile(t *testing.T) {
 	for k, want := range files {
 		got := stats.Files[k]
 		size, _ := strconv.Atoi(want[2])
-		if got.Name != want[0] || got.Extension != want[1] || got.Size != int64(size) {
+		if got.Name != want[0] || got.Path != want[0] || got.Extension != want[1] || got.Size != int64(size) {
 			t.Errorf("Expected %+v got %+v", want, got)
 		}
 	}
@@ -0,0 +1,106 @@
+package index
+
+import (
+	"math"
+
+	"github.com/wpdirectory/wpdir/internal/codesearch/index"
+	"github.com/wpdirectory/wpdir/internal/codesearch/regexp"
+)
+
+// Analysis describes how well a search pattern is filtered by the
+// trigram index before files are grepped
+type Analysis struct {
+	// Query is the trigram query computed from the pattern
+	Query string `json:"query"`
+	// Trigrams is the fewest trigrams a file must contain to be grepped
+	Trigrams int `json:"trigrams"`
+	// NoMatch is set when no file can match, so nothing is grepped
+	NoMatch bool `json:"no_match,omitempty"`
+}
+
+// noMatch is the fewest trigrams counted for a query no file can match
+const noMatch = math.MaxInt32
+
+// newAnalysis describes the trigram query q
+func newAnalysis(q *index.Query) *Analysis {
+	n := minTrigrams(q)
+	if n == noMatch {
+		return &Analysis{Query: q.String(), NoMatch: true}
+	}
+	return &Analysis{Query: q.String(), Trigrams: n}
+}
+
+// Unfiltered reports whether every file would need to be grepped
+func (a *Analysis) Unfiltered() bool {
+	return !a.NoMatch && a.Trigrams == 0
+}
+
+// Weak reports whether the trigram filter is likely to match a large
+// number of files
+func (a *Analysis) Weak() bool {
+	return a.Trigrams == 1
+}
+
+// Analyze computes the trigram query used to filter files for a pattern
+func Analyze(pat string, ignoreCase bool) (*Analysis, error) {
+	re, err := regexp.Compile(GetRegexpPattern(pat, ignoreCase))
+	if err != nil {
+		return nil, err
+	}
+
+	return newAnalysis(index.RegexpQuery(re.Syntax)), nil
+}
+
+// minTrigrams returns the fewest trigrams a file must contain to match q,
+// or noMatch if no file can match it
+func minTrigrams(q *index.Query) int {
+	switch q.Op {
+	case index.QAll:
+		return 0
+	case index.QNone:
+		return noMatch
+	case index.QAnd:
+		n := len(q.Trigram)
+		for _, sub := range q.Sub {
+			m := minTrigrams(sub)
+			if m == noMatch {
+				return noMatch
+			}
+			n += m
+		}
+		return n
+	case index.QOr:
+		n := -1
+		if len(q.Trigram) > 0 {
+			n = 1
+		}
+		for _, sub := range q.Sub {
+			if m := minTrigrams(sub); n < 0 || m < n {
+				n = m
+			}
+		}
+		if n < 0 {
+			return 0
+		}
+		return n
+	}
+	return 0
+}
+
+// AnalyzeInput computes the trigram query used to filter files for search
+// input in the given mode
+func AnalyzeInput(input, mode string, ignoreCase bool) (*Analysis, error) {
+	if mode == ModeQuery {
+		q, err := ParseQuery(input)
+		if err != nil {
+			return nil, err
+		}
+		return q.Analyze(ignoreCase)
+	}
+
+	pat, err := BuildPattern(input, mode)
+	if err != nil {
+		return nil, err
+	}
+	return Analyze(pat, ignoreCase)
+}
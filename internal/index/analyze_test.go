@@ -0,0 +1,39 @@
+package index
+
+import (
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		pat        string
+		unfiltered bool
+		weak       bool
+	}{
+		{`.*`, true, false},
+		{`(a|b)*c`, true, false},
+		{`\w+`, true, false},
+		{`abc`, false, true},
+		{`unserialize\(`, false, false},
+		{`wp_ajax_(nopriv_)?\w+`, false, false},
+		{`eval|abcd`, false, false},
+		{`abc|xyz12`, false, true},
+		{`[^\x00-\x{10FFFF}]`, false, false},
+		{`abc[^\x00-\x{10FFFF}]`, false, false},
+	}
+
+	for _, test := range tests {
+		a, err := Analyze(test.pat, false)
+		if err != nil {
+			t.Fatalf("Could not analyze %s: %s", test.pat, err)
+		}
+		if a.Unfiltered() != test.unfiltered || a.Weak() != test.weak {
+			t.Errorf("Expected unfiltered %t weak %t got %t %t for %s (%s)",
+				test.unfiltered, test.weak, a.Unfiltered(), a.Weak(), test.pat, a.Query)
+		}
+	}
+
+	if _, err := Analyze(`(unclosed`, false); err == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+}
@@ -0,0 +1,438 @@
+package index
+
+import (
+	"bytes"
+	"path/filepath"
+	goregexp "regexp"
+	"sort"
+	"strings"
+)
+
+// language identifies the comment syntax used by a file
+type language int
+
+const (
+	langUnknown language = iota
+	langPHP
+	langJS
+	langCSS
+	langSCSS
+)
+
+// languages maps file extensions to the comment syntax they use
+var languages = map[string]language{
+	".php":   langPHP,
+	".php3":  langPHP,
+	".php4":  langPHP,
+	".php5":  langPHP,
+	".php7":  langPHP,
+	".phtml": langPHP,
+	".inc":   langPHP,
+	".js":    langJS,
+	".jsx":   langJS,
+	".mjs":   langJS,
+	".ts":    langJS,
+	".tsx":   langJS,
+	".css":   langCSS,
+	".scss":  langSCSS,
+	".less":  langSCSS,
+}
+
+// languageOf returns the language of a file based on its extension
+func languageOf(filename string) language {
+	return languages[strings.ToLower(filepath.Ext(filename))]
+}
+
+// span is a half open byte range [start, end) within a file
+type span struct {
+	start int
+	end   int
+}
+
+// lineFilter decides whether a matching line should be skipped.
+// reset is called with the full contents of each file before grepping.
+type lineFilter interface {
+	reset(buf []byte)
+	skip(start, end int) bool
+}
+
+// commentFilter skips lines where every match falls inside a comment
+type commentFilter struct {
+	re       *goregexp.Regexp
+	lang     language
+	buf      []byte
+	comments []span
+}
+
+// newCommentFilter returns a commentFilter matching the search pattern
+func newCommentFilter(pat string) (*commentFilter, error) {
+	re, err := goregexp.Compile(pat)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commentFilter{
+		re: re,
+	}, nil
+}
+
+// forFile returns a lineFilter for the named file, or nil if the
+// comment syntax of the file is not known
+func (f *commentFilter) forFile(filename string) lineFilter {
+	lang := languageOf(filename)
+	if lang == langUnknown {
+		return nil
+	}
+	f.lang = lang
+	return f
+}
+
+func (f *commentFilter) reset(buf []byte) {
+	f.buf = buf
+	f.comments = lexComments(f.lang, buf)
+}
+
+// skip reports whether all matches on the line buf[start:end] are
+// fully contained within comments
+func (f *commentFilter) skip(start, end int) bool {
+	if len(f.comments) == 0 {
+		return false
+	}
+
+	line := bytes.TrimRight(f.buf[start:end], "\n")
+	matches := f.re.FindAllIndex(line, -1)
+	if len(matches) == 0 {
+		return false
+	}
+
+	for _, m := range matches {
+		if !f.inComment(start+m[0], start+m[1]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// inComment reports whether [start, end) is inside a single comment
+func (f *commentFilter) inComment(start, end int) bool {
+	i := sort.Search(len(f.comments), func(i int) bool {
+		return f.comments[i].end > start
+	})
+	if i == len(f.comments) {
+		return false
+	}
+
+	c := f.comments[i]
+	return c.start <= start && end <= c.end
+}
+
+// lexComments returns the location of all comments in src
+func lexComments(lang language, src []byte) []span {
+	l := &lexer{
+		src:  src,
+		lang: lang,
+	}
+
+	switch lang {
+	case langPHP:
+		l.lexPHP()
+	case langJS:
+		l.lexJS(false)
+	case langCSS, langSCSS:
+		l.lexCSS()
+	}
+
+	return l.comments
+}
+
+// lexer holds the state used while finding comments
+type lexer struct {
+	src      []byte
+	pos      int
+	lang     language
+	comments []span
+}
+
+func (l *lexer) hasPrefix(s string) bool {
+	return bytes.HasPrefix(l.src[l.pos:], []byte(s))
+}
+
+func (l *lexer) addComment(start int) {
+	l.comments = append(l.comments, span{start, l.pos})
+}
+
+// lineComment consumes a comment running to the end of the line.
+// PHP line comments also end at a closing tag.
+func (l *lexer) lineComment() {
+	start := l.pos
+	for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+		if l.lang == langPHP && l.hasPrefix("?>") {
+			break
+		}
+		l.pos++
+	}
+	l.addComment(start)
+}
+
+// blockComment consumes a /* */ comment, including docblocks.
+// Unterminated comments run to the end of the file.
+func (l *lexer) blockComment() {
+	start := l.pos
+	end := bytes.Index(l.src[l.pos+2:], []byte("*/"))
+	if end < 0 {
+		l.pos = len(l.src)
+	} else {
+		l.pos += end + 4
+	}
+	l.addComment(start)
+}
+
+// quoted consumes a string delimited by q, honouring backslash escapes.
+// If stopAtNewline is set an unterminated string ends at the line break.
+func (l *lexer) quoted(q byte, stopAtNewline bool) {
+	l.pos++
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == '\\':
+			l.pos += 2
+			continue
+		case c == q:
+			l.pos++
+			return
+		case c == '\n' && stopAtNewline:
+			return
+		}
+		l.pos++
+	}
+	if l.pos > len(l.src) {
+		l.pos = len(l.src)
+	}
+}
+
+// lexPHP finds comments within the PHP blocks of a file
+// Content outside of <?php ?> tags is treated as inline HTML
+func (l *lexer) lexPHP() {
+	for l.pos < len(l.src) {
+		// Inline HTML, find the next open tag
+		open := bytes.Index(l.src[l.pos:], []byte("<?"))
+		if open < 0 {
+			return
+		}
+		l.pos += open + 2
+		if bytes.HasPrefix(bytes.ToLower(l.src[l.pos:]), []byte("xml")) {
+			continue
+		}
+
+		l.lexPHPCode()
+	}
+}
+
+// lexPHPCode consumes PHP code until the closing tag
+func (l *lexer) lexPHPCode() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case l.hasPrefix("?>"):
+			l.pos += 2
+			return
+		case l.hasPrefix("#["):
+			// PHP 8 attribute
+			l.pos += 2
+		case c == '#' || l.hasPrefix("//"):
+			l.lineComment()
+		case l.hasPrefix("/*"):
+			l.blockComment()
+		case c == '\'' || c == '"' || c == '`':
+			l.quoted(c, false)
+		case l.hasPrefix("<<<"):
+			l.heredoc()
+		default:
+			l.pos++
+		}
+	}
+}
+
+// heredoc consumes a PHP heredoc or nowdoc string
+func (l *lexer) heredoc() {
+	l.pos += 3
+	for l.pos < len(l.src) && (l.src[l.pos] == ' ' || l.src[l.pos] == '\t') {
+		l.pos++
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == '\'' || l.src[l.pos] == '"') {
+		l.pos++
+	}
+
+	start := l.pos
+	for l.pos < len(l.src) && isIdentByte(l.src[l.pos]) {
+		l.pos++
+	}
+	id := l.src[start:l.pos]
+	if len(id) == 0 {
+		// Not a heredoc, e.g. a shift followed by a comparison
+		return
+	}
+
+	// Skip to the body
+	nl := bytes.IndexByte(l.src[l.pos:], '\n')
+	if nl < 0 {
+		l.pos = len(l.src)
+		return
+	}
+	l.pos += nl + 1
+
+	// The closing identifier is the first on a line, optionally
+	// indented (PHP 7.3+) and not followed by an identifier byte
+	for l.pos < len(l.src) {
+		line := l.pos
+		for line < len(l.src) && (l.src[line] == ' ' || l.src[line] == '\t') {
+			line++
+		}
+		if bytes.HasPrefix(l.src[line:], id) {
+			end := line + len(id)
+			if end >= len(l.src) || !isIdentByte(l.src[end]) {
+				l.pos = end
+				return
+			}
+		}
+
+		nl := bytes.IndexByte(l.src[l.pos:], '\n')
+		if nl < 0 {
+			l.pos = len(l.src)
+			return
+		}
+		l.pos += nl + 1
+	}
+}
+
+// lexJS finds comments in JavaScript. When inTemplate is set it returns
+// at the brace closing a template literal substitution.
+func (l *lexer) lexJS(inTemplate bool) {
+	depth := 0
+	regexOK := true
+
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case l.hasPrefix("//"):
+			l.lineComment()
+			continue
+		case l.hasPrefix("/*"):
+			l.blockComment()
+			continue
+		case c == '\'' || c == '"':
+			l.quoted(c, true)
+			regexOK = false
+			continue
+		case c == '`':
+			l.template()
+			regexOK = false
+			continue
+		case c == '/' && regexOK:
+			l.regexLiteral()
+			regexOK = false
+			continue
+		case c == '{':
+			depth++
+		case c == '}':
+			if inTemplate && depth == 0 {
+				l.pos++
+				return
+			}
+			depth--
+		}
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			// Whitespace does not change what may follow
+		case isIdentByte(c) || c == ')' || c == ']' || c == '}':
+			regexOK = false
+		default:
+			regexOK = true
+		}
+		l.pos++
+	}
+}
+
+// template consumes a JavaScript template literal, lexing the code
+// within any ${} substitutions.
+func (l *lexer) template() {
+	l.pos++
+	for l.pos < len(l.src) {
+		switch {
+		case l.src[l.pos] == '\\':
+			l.pos += 2
+		case l.src[l.pos] == '`':
+			l.pos++
+			return
+		case l.hasPrefix("${"):
+			l.pos += 2
+			l.lexJS(true)
+		default:
+			l.pos++
+		}
+	}
+	if l.pos > len(l.src) {
+		l.pos = len(l.src)
+	}
+}
+
+// regexLiteral consumes a JavaScript regular expression literal
+func (l *lexer) regexLiteral() {
+	l.pos++
+	inClass := false
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				l.pos++
+				return
+			}
+		case '\n':
+			return
+		}
+		l.pos++
+	}
+	if l.pos > len(l.src) {
+		l.pos = len(l.src)
+	}
+}
+
+// lexCSS finds comments in CSS. SCSS and LESS also allow line comments.
+func (l *lexer) lexCSS() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case l.hasPrefix("/*"):
+			l.blockComment()
+		case l.lang == langSCSS && l.hasPrefix("//"):
+			l.lineComment()
+		case c == '\'' || c == '"':
+			l.quoted(c, true)
+		case l.hasPrefix("url("):
+			// Unquoted URLs may contain //
+			end := bytes.IndexByte(l.src[l.pos:], ')')
+			if end < 0 {
+				l.pos = len(l.src)
+			} else {
+				l.pos += end + 1
+			}
+		default:
+			l.pos++
+		}
+	}
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' ||
+		'a' <= c && c <= 'z' ||
+		'A' <= c && c <= 'Z' ||
+		'0' <= c && c <= '9' ||
+		c >= 0x80
+}
@@ -0,0 +1,186 @@
+package index
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func commentText(lang language, src string) []string {
+	var got []string
+	for _, c := range lexComments(lang, []byte(src)) {
+		got = append(got, src[c.start:c.end])
+	}
+	return got
+}
+
+func TestLanguageOf(t *testing.T) {
+	tests := map[string]language{
+		"plugin.php":          langPHP,
+		"inc/Admin.PHP":       langPHP,
+		"assets/js/app.js":    langJS,
+		"assets/css/app.css":  langCSS,
+		"assets/scss/_x.scss": langSCSS,
+		"readme.txt":          langUnknown,
+		"Makefile":            langUnknown,
+	}
+
+	for name, want := range tests {
+		got := languageOf(name)
+		if got != want {
+			t.Errorf("Expected %d got %d for %s", want, got, name)
+		}
+	}
+}
+
+func TestLexCommentsPHP(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{
+			src:  "<?php\n// line\nfoo(); # hash\n",
+			want: []string{"// line", "# hash"},
+		},
+		{
+			src:  "<?php\n/**\n * Docblock unserialize(\n */\nunserialize( $x );",
+			want: []string{"/**\n * Docblock unserialize(\n */"},
+		},
+		{
+			src:  "<?php $a = '// not a comment'; $b = \"/* nor \\\" this */\";",
+			want: nil,
+		},
+		{
+			src:  "<h1>// html</h1><?php echo 1; // php ?> <p># html</p>",
+			want: []string{"// php "},
+		},
+		{
+			src:  "<?php #[Attribute]\nclass A {}",
+			want: nil,
+		},
+		{
+			src:  "<?php\n$s = <<<EOT\n// inside heredoc\nEOT;\n// after",
+			want: []string{"// after"},
+		},
+		{
+			src:  "<?php\n$s = <<<'EOT'\n  # nowdoc\n  EOT;\n# after",
+			want: []string{"# after"},
+		},
+		{
+			src:  "<?php\n$s = <<<\"EOT\"\nEOTX /* still string */\nEOT . '';\n/* after */",
+			want: []string{"/* after */"},
+		},
+		{
+			src:  "<?php /* unterminated",
+			want: []string{"/* unterminated"},
+		},
+	}
+
+	for _, test := range tests {
+		got := commentText(langPHP, test.src)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Expected %q got %q for %q", test.want, got, test.src)
+		}
+	}
+}
+
+func TestLexCommentsJS(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{
+			src:  "var a = 1; // line\n/* block */ b();",
+			want: []string{"// line", "/* block */"},
+		},
+		{
+			src:  "var url = 'http://example.com'; var s = \"/* x */\";",
+			want: nil,
+		},
+		{
+			src:  "var re = /\\/\\/[a/]*/g; // real",
+			want: []string{"// real"},
+		},
+		{
+			src:  "var x = a / b; // divide",
+			want: []string{"// divide"},
+		},
+		{
+			src:  "var t = `// ${ f({a: 1}) /* sub */ } //`; // end",
+			want: []string{"/* sub */", "// end"},
+		},
+	}
+
+	for _, test := range tests {
+		got := commentText(langJS, test.src)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Expected %q got %q for %q", test.want, got, test.src)
+		}
+	}
+}
+
+func TestLexCommentsCSS(t *testing.T) {
+	tests := []struct {
+		lang language
+		src  string
+		want []string
+	}{
+		{
+			lang: langCSS,
+			src:  "a { color: red; } /* note */ b { background: url(//cdn.example.com/x.png); }",
+			want: []string{"/* note */"},
+		},
+		{
+			lang: langCSS,
+			src:  "a { content: '/* not */'; } // not in css",
+			want: nil,
+		},
+		{
+			lang: langSCSS,
+			src:  "$x: 1px; // scss\na { background: url(http://example.com); }",
+			want: []string{"// scss"},
+		},
+	}
+
+	for _, test := range tests {
+		got := commentText(test.lang, test.src)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Expected %q got %q for %q", test.want, got, test.src)
+		}
+	}
+}
+
+func TestCommentFilterSkip(t *testing.T) {
+	src := strings.Join([]string{
+		"<?php",
+		"// unserialize( $commented );",
+		"$a = unserialize( $b ); // unserialize(",
+		"/* unserialize( */ unserialize( $c );",
+		" * @see unserialize(",
+		"",
+	}, "\n")
+
+	cf, err := newCommentFilter(GetRegexpPattern(`unserialize\(`, false))
+	if err != nil {
+		t.Fatalf("Could not create filter: %s", err)
+	}
+	f := cf.forFile("test.php")
+	f.reset([]byte(src))
+
+	want := []bool{false, true, false, false, false}
+	start := 0
+	for i, line := range strings.SplitAfter(src, "\n") {
+		if i == len(want) {
+			break
+		}
+		got := f.skip(start, start+len(line))
+		if got != want[i] {
+			t.Errorf("Expected %t got %t for line %q", want[i], got, line)
+		}
+		start += len(line)
+	}
+
+	if cf.forFile("readme.txt") != nil {
+		t.Errorf("Expected no filter for unknown file types")
+	}
+}
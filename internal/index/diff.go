@@ -0,0 +1,242 @@
+package index
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	// diffContext is the number of unchanged lines shown around changes
+	diffContext = 3
+	// maxDiffSize is the largest file, in bytes, which is diffed line by
+	// line. Larger files are only reported as modified.
+	maxDiffSize = 512 * 1024
+)
+
+const (
+	// FileAdded marks a file only in the newer Tree
+	FileAdded = "added"
+	// FileRemoved marks a file only in the older Tree
+	FileRemoved = "removed"
+	// FileModified marks a file whose contents changed
+	FileModified = "modified"
+)
+
+// Tree is a set of text files which can be compared with another Tree
+type Tree interface {
+	// Files returns the file names, using forward slashes
+	Files() ([]string, error)
+	ReadFile(name string) ([]byte, error)
+}
+
+// FileDiff describes the change to a single file between two Trees
+type FileDiff struct {
+	File   string `json:"file"`
+	Status string `json:"status"`
+	// Diff is a unified diff, empty if the file is too large to compare
+	Diff string `json:"diff,omitempty"`
+}
+
+// Tree returns the raw files stored alongside the index
+func (r *IndexRef) Tree() Tree {
+	return &rawTree{dir: filepath.Join(r.dir, "raw")}
+}
+
+// rawTree reads the gzipped copies of indexed files
+type rawTree struct {
+	dir string
+}
+
+func (t *rawTree) Files() ([]string, error) {
+	var names []string
+	err := filepath.Walk(t.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		name, err := filepath.Rel(t.dir, path)
+		if err != nil {
+			return err
+		}
+		names = append(names, filepath.ToSlash(name))
+		return nil
+	})
+	return names, err
+}
+
+func (t *rawTree) ReadFile(name string) ([]byte, error) {
+	f, err := os.Open(filepath.Join(t.dir, filepath.FromSlash(name)))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	g, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer g.Close()
+
+	return ioutil.ReadAll(g)
+}
+
+// zipTree reads the text files in an Extension archive, skipping the files
+// which would be excluded from an index
+type zipTree struct {
+	files map[string]*zip.File
+}
+
+// ZipTree returns the text files in an archive
+func ZipTree(archive []byte) (Tree, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+
+	t := &zipTree{files: make(map[string]*zip.File)}
+	for _, file := range zr.File {
+		info := file.FileInfo()
+		if info.IsDir() || file.Name[0] == '.' || info.Mode()&os.ModeType != 0 {
+			continue
+		}
+		txt, err := isZipTextFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if txt {
+			t.files[file.Name] = file
+		}
+	}
+	return t, nil
+}
+
+func (t *zipTree) Files() ([]string, error) {
+	names := make([]string, 0, len(t.files))
+	for name := range t.files {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (t *zipTree) ReadFile(name string) ([]byte, error) {
+	file, ok := t.files[name]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return ioutil.ReadAll(r)
+}
+
+// Diff compares two Trees, returning the changed files in name order
+func Diff(from, to Tree) ([]*FileDiff, error) {
+	fromFiles, err := from.Files()
+	if err != nil {
+		return nil, err
+	}
+	toFiles, err := to.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	inFrom := make(map[string]bool, len(fromFiles))
+	for _, name := range fromFiles {
+		inFrom[name] = true
+	}
+	inTo := make(map[string]bool, len(toFiles))
+	names := append([]string(nil), fromFiles...)
+	for _, name := range toFiles {
+		inTo[name] = true
+		if !inFrom[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diffs []*FileDiff
+	for _, name := range names {
+		var a, b []byte
+		if inFrom[name] {
+			if a, err = from.ReadFile(name); err != nil {
+				return nil, err
+			}
+		}
+		if inTo[name] {
+			if b, err = to.ReadFile(name); err != nil {
+				return nil, err
+			}
+		}
+
+		fd := &FileDiff{File: name, Status: FileModified}
+		switch {
+		case !inFrom[name]:
+			fd.Status = FileAdded
+		case !inTo[name]:
+			fd.Status = FileRemoved
+		case bytes.Equal(a, b):
+			continue
+		}
+
+		if len(a) <= maxDiffSize && len(b) <= maxDiffSize {
+			if fd.Diff, err = unifiedDiff(name, a, b, inFrom[name], inTo[name]); err != nil {
+				return nil, err
+			}
+		}
+		diffs = append(diffs, fd)
+	}
+
+	return diffs, nil
+}
+
+// unifiedDiff returns the unified diff of a file, added and removed files
+// are compared with /dev/null as in git
+func unifiedDiff(name string, a, b []byte, inFrom, inTo bool) (string, error) {
+	fromFile, toFile := "a/"+name, "b/"+name
+	if !inFrom {
+		fromFile = "/dev/null"
+	}
+	if !inTo {
+		toFile = "/dev/null"
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(a),
+		B:        splitLines(b),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  diffContext,
+	})
+	if err != nil {
+		return "", err
+	}
+	return "diff --git a/" + name + " b/" + name + "\n" + diff, nil
+}
+
+// splitLines splits text into lines keeping line endings, an empty text
+// has no lines
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(b), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] += "\n\\ No newline at end of file\n"
+	}
+	return lines
+}
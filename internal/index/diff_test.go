@@ -0,0 +1,68 @@
+package index
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	from := buildTestIndex(t, map[string]string{
+		"hello.php":  "<?php\n$a = 1;\n$b = 2;\n",
+		"old.php":    "<?php\n",
+		"readme.txt": "Hello\n",
+	})
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"hello.php":  "<?php\n$a = 1;\n$b = eval($c);\n",
+		"new.php":    "<?php\n",
+		"readme.txt": "Hello\n",
+		".hidden":    "secret\n",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	to, err := ZipTree(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Could not read archive: %s", err)
+	}
+
+	diffs, err := Diff(from.Ref.Tree(), to)
+	if err != nil {
+		t.Fatalf("Could not diff: %s", err)
+	}
+
+	got := make(map[string]string)
+	for _, fd := range diffs {
+		got[fd.File] = fd.Status
+	}
+	want := map[string]string{
+		"hello.php": FileModified,
+		"new.php":   FileAdded,
+		"old.php":   FileRemoved,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v got %+v", want, got)
+	}
+
+	patch := "diff --git a/hello.php b/hello.php\n" +
+		"--- a/hello.php\n" +
+		"+++ b/hello.php\n" +
+		"@@ -1,3 +1,3 @@\n" +
+		" <?php\n" +
+		" $a = 1;\n" +
+		"-$b = 2;\n" +
+		"+$b = eval($c);\n"
+	if diffs[0].Diff != patch {
+		t.Errorf("Expected %q got %q", patch, diffs[0].Diff)
+	}
+}
@@ -12,7 +12,8 @@ import (
 var nl = []byte{'\n'}
 
 type grepper struct {
-	buf []byte
+	buf    []byte
+	filter lineFilter
 }
 
 func countLines(b []byte) int {
@@ -142,6 +143,12 @@ func (g *grepper) grep2(
 		return err
 	}
 
+	if g.filter != nil {
+		g.filter.reset(buf)
+	}
+
+	// offset of buf within the file
+	off := 0
 	lineno := 0
 	for {
 		if len(buf) == 0 {
@@ -170,6 +177,13 @@ func (g *grepper) grep2(
 
 		lineno += countLines(buf[:str])
 
+		if g.filter != nil && g.filter.skip(off+str, off+end) {
+			lineno++
+			buf = buf[end:]
+			off += end
+			continue
+		}
+
 		more, err := fn(
 			bytes.TrimRight(buf[str:end], "\n"),
 			lineno+1,
@@ -184,6 +198,7 @@ func (g *grepper) grep2(
 
 		lineno++
 		buf = buf[end:]
+		off += end
 	}
 }
 
@@ -4,12 +4,14 @@ import (
 	"archive/zip"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/gob"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	goregexp "regexp"
 	"sync"
 	"time"
 	"unicode/utf8"
@@ -36,6 +38,10 @@ const (
 type Index struct {
 	Ref *IndexRef
 	idx *index.Index
+	// refs counts the readers of the index files which have acquired it,
+	// the files of a destroyed index are kept until they are released
+	refs      int
+	destroyed bool
 	sync.RWMutex
 }
 
@@ -51,13 +57,29 @@ type SearchOptions struct {
 	IgnoreComments bool
 	Offset         int
 	Limit          int
+	Mode           string
+	Scope          string
 }
 
+const (
+	// ModeRegex treats the search input as a regular expression
+	ModeRegex = "regex"
+	// ModeLiteral matches the search input exactly
+	ModeLiteral = "literal"
+	// ModeWord matches the search input exactly as a whole word
+	ModeWord = "word"
+	// ModeQuery treats the search input as a BoolQuery
+	ModeQuery = "query"
+)
+
 type Match struct {
 	Line       string
 	LineNumber int
 	Before     []string
 	After      []string
+	// Start and End are the byte offsets of the first match within Line
+	Start int
+	End   int
 }
 
 type SearchResponse struct {
@@ -67,6 +89,8 @@ type SearchResponse struct {
 	FilesOpened    int           `json:"-"`
 	Duration       time.Duration `json:"-"`
 	Revision       string
+	// Version is the Extension version the index was built from
+	Version string
 }
 
 type FileMatch struct {
@@ -83,12 +107,26 @@ type IndexRef struct {
 	Time time.Time
 	dir  string
 	Slug string
+	// Version is empty for indexes built before versions were recorded
+	Version string
+	// Ref is the version control ref indexed, such as trunk, a tag or a
+	// branch, and Commit the revision or commit it was at. Both are empty
+	// for downloaded archives.
+	Ref    string
+	Commit string
 }
 
 func (r *IndexRef) Dir() string {
 	return r.dir
 }
 
+// SetRef records the version control ref the index was built from
+func (r *IndexRef) SetRef(ref, commit string) error {
+	r.Ref = ref
+	r.Commit = commit
+	return r.writeManifest()
+}
+
 func (r *IndexRef) writeManifest() error {
 	w, err := os.Create(filepath.Join(r.dir, manifestFilename))
 	if err != nil {
@@ -116,15 +154,74 @@ func (n *Index) Close() error {
 	return n.idx.Close()
 }
 
+// Destroy closes the index and removes its files, once any readers which
+// acquired it have released it
 func (n *Index) Destroy() error {
 	n.Lock()
 	defer n.Unlock()
 	if err := n.idx.Close(); err != nil {
 		return err
 	}
+	n.destroyed = true
+	if n.refs > 0 {
+		return nil
+	}
+	return n.Ref.Remove()
+}
+
+// Acquire keeps the index files until Release is called, it reports false
+// if the index has already been destroyed
+func (n *Index) Acquire() bool {
+	n.Lock()
+	defer n.Unlock()
+	if n.destroyed {
+		return false
+	}
+	n.refs++
+	return true
+}
+
+// Release lets the index files be removed, when the index was destroyed
+// while they were being read
+func (n *Index) Release() error {
+	n.Lock()
+	defer n.Unlock()
+	n.refs--
+	if n.refs > 0 || !n.destroyed {
+		return nil
+	}
 	return n.Ref.Remove()
 }
 
+// Files returns the names of the indexed files
+// The index only records the paths of the archive root, so names are read
+// from the file list.
+func (n *Index) Files() []string {
+	n.RLock()
+	defer n.RUnlock()
+
+	files := make([]string, n.idx.NumNames())
+	for i := range files {
+		files[i] = n.idx.Name(uint32(i))
+	}
+	return files
+}
+
+// ExcludedFiles returns the files left out of the index and why
+func (r *IndexRef) ExcludedFiles() ([]*ExcludedFile, error) {
+	f, err := os.Open(filepath.Join(r.dir, excludedFileJSONFilename))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var files []*ExcludedFile
+	if err := json.NewDecoder(f).Decode(&files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
 // GetDir ...
 func (n *Index) GetDir() string {
 	return n.Ref.dir
@@ -146,18 +243,87 @@ func GetRegexpPattern(pat string, ignoreCase bool) string {
 	return "(?m)" + pat
 }
 
+// BuildPattern returns the regular expression used to search for the input
+// Literal input has meta characters escaped, words are also wrapped in word
+// boundaries where the input begins or ends with a word character.
+func BuildPattern(input, mode string) (string, error) {
+	switch mode {
+	case "", ModeRegex:
+		return input, nil
+	case ModeLiteral:
+		return goregexp.QuoteMeta(input), nil
+	case ModeWord:
+		pat := goregexp.QuoteMeta(input)
+		if input == "" {
+			return pat, nil
+		}
+		if isWordChar(input[0]) {
+			pat = `\b` + pat
+		}
+		if isWordChar(input[len(input)-1]) {
+			pat = pat + `\b`
+		}
+		return pat, nil
+	default:
+		return "", fmt.Errorf("unknown search mode: %s", mode)
+	}
+}
+
+// ValidMode reports whether mode is a known search mode
+func ValidMode(mode string) bool {
+	switch mode {
+	case "", ModeRegex, ModeLiteral, ModeWord, ModeQuery:
+		return true
+	}
+	return false
+}
+
+// matchSpan returns the byte offsets of the first match of re in line
+func matchSpan(re *goregexp.Regexp, line []byte) (int, int) {
+	loc := re.FindIndex(line)
+	if loc == nil {
+		return 0, 0
+	}
+	return loc[0], loc[1]
+}
+
+// isWordChar reports whether c matches \w
+func isWordChar(c byte) bool {
+	return c == '_' ||
+		'a' <= c && c <= 'z' ||
+		'A' <= c && c <= 'Z' ||
+		'0' <= c && c <= '9'
+}
+
 // Search ...
-func (n *Index) Search(pat, slug string, opt *SearchOptions) (*SearchResponse, error) {
+// If ctx is done before all files are searched, the matches found so far
+// are returned along with the context error.
+func (n *Index) Search(ctx context.Context, pat, slug string, opt *SearchOptions) (*SearchResponse, error) {
 	startedAt := time.Now()
 
 	n.RLock()
 	defer n.RUnlock()
 
+	if opt.Mode == ModeQuery {
+		return n.searchQuery(ctx, pat, opt)
+	}
+
+	pat, err := BuildPattern(pat, opt.Mode)
+	if err != nil {
+		return nil, err
+	}
+
 	re, err := regexp.Compile(GetRegexpPattern(pat, opt.IgnoreCase))
 	if err != nil {
 		return nil, err
 	}
 
+	// Used to find where the match is within each matching line
+	spanRe, err := goregexp.Compile(GetRegexpPattern(pat, opt.IgnoreCase))
+	if err != nil {
+		return nil, err
+	}
+
 	var (
 		g                grepper
 		results          []*FileMatch
@@ -175,8 +341,20 @@ func (n *Index) Search(pat, slug string, opt *SearchOptions) (*SearchResponse, e
 		}
 	}
 
+	var cf *commentFilter
+	if opt.IgnoreComments {
+		cf, err = newCommentFilter(GetRegexpPattern(pat, opt.IgnoreCase))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	files := n.idx.PostingQuery(index.RegexpQuery(re.Syntax))
 	for _, file := range files {
+		if ctx.Err() != nil {
+			break
+		}
+
 		var matches []*Match
 		name := n.idx.Name(file)
 		hasMatch := false
@@ -186,6 +364,11 @@ func (n *Index) Search(pat, slug string, opt *SearchOptions) (*SearchResponse, e
 			continue
 		}
 
+		// drop matches inside comments for known languages
+		if cf != nil {
+			g.filter = cf.forFile(name)
+		}
+
 		filesOpened++
 		if err := g.grep2File(filepath.Join(n.Ref.dir, "raw", name), re, int(opt.LinesOfContext),
 			func(line []byte, lineno int, before [][]byte, after [][]byte) (bool, error) {
@@ -196,11 +379,14 @@ func (n *Index) Search(pat, slug string, opt *SearchOptions) (*SearchResponse, e
 				}
 
 				matchesCollected++
+				start, end := matchSpan(spanRe, line)
 				matches = append(matches, &Match{
 					Line:       string(line),
 					LineNumber: lineno,
 					Before:     toStrings(before),
 					After:      toStrings(after),
+					Start:      start,
+					End:        end,
 				})
 
 				if matchesCollected > matchLimit {
@@ -231,7 +417,8 @@ func (n *Index) Search(pat, slug string, opt *SearchOptions) (*SearchResponse, e
 		FilesWithMatch: filesFound,
 		FilesOpened:    filesOpened,
 		Duration:       time.Now().Sub(startedAt),
-	}, nil
+		Version:        n.Ref.Version,
+	}, ctx.Err()
 }
 
 func isBinaryFile(filename string) (bool, error) {
@@ -375,7 +562,7 @@ func Open(dir string) (*Index, error) {
 }
 
 // BuildFromZip ...
-func BuildFromZip(opt *IndexOptions, archive []byte, dst, slug string) (*IndexRef, *filestats.Stats, error) {
+func BuildFromZip(opt *IndexOptions, archive []byte, dst, slug, version string) (*IndexRef, *filestats.Stats, error) {
 
 	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
 	if err != nil {
@@ -396,9 +583,10 @@ func BuildFromZip(opt *IndexOptions, archive []byte, dst, slug string) (*IndexRe
 	}
 
 	r := &IndexRef{
-		Time: time.Now(),
-		dir:  dst,
-		Slug: slug,
+		Time:    time.Now(),
+		dir:     dst,
+		Slug:    slug,
+		Version: version,
 	}
 
 	if err := r.writeManifest(); err != nil {
@@ -0,0 +1,129 @@
+package index
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestBuildPattern(t *testing.T) {
+	tests := []struct {
+		input string
+		mode  string
+		want  string
+	}{
+		{`wp_ajax_\w+`, "", `wp_ajax_\w+`},
+		{`wp_ajax_\w+`, ModeRegex, `wp_ajax_\w+`},
+		{`$wpdb->prepare(`, ModeLiteral, `\$wpdb->prepare\(`},
+		{`eval`, ModeWord, `\beval\b`},
+		{`$wpdb->prepare(`, ModeWord, `\$wpdb->prepare\(`},
+		{`->query`, ModeWord, `->query\b`},
+	}
+
+	for _, test := range tests {
+		got, err := BuildPattern(test.input, test.mode)
+		if err != nil {
+			t.Fatalf("Could not build pattern for %s: %s", test.input, err)
+		}
+		if got != test.want {
+			t.Errorf("Expected %s got %s", test.want, got)
+		}
+	}
+
+	if _, err := BuildPattern("eval", "fuzzy"); err == nil {
+		t.Errorf("Expected an error for an unknown mode")
+	}
+}
+
+func TestSearchMatchSpan(t *testing.T) {
+	idx := buildTestIndex(t, map[string]string{
+		"a.php": "<?php\n$data = unserialize( $_POST['x'] );\n",
+	})
+
+	resp, err := idx.Search(context.Background(), `unserialize\(`, "test", &SearchOptions{})
+	if err != nil {
+		t.Fatalf("Could not search: %s", err)
+	}
+	if len(resp.Matches) != 1 || len(resp.Matches[0].Matches) != 1 {
+		t.Fatalf("Expected a single match got %+v", resp.Matches)
+	}
+
+	m := resp.Matches[0].Matches[0]
+	if got := m.Line[m.Start:m.End]; got != "unserialize(" {
+		t.Errorf("Expected unserialize( got %q", got)
+	}
+}
+
+func TestIndexFiles(t *testing.T) {
+	idx := buildTestIndex(t, map[string]string{
+		"hello.php": "<?php\necho 'hello';\n",
+		"style.css": "body {}\n",
+		"logo.png":  "\x89PNG\r\n\x1a\n\xff\xfe",
+	})
+
+	files := idx.Files()
+	sort.Strings(files)
+	if want := []string{"hello.php", "style.css"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("Expected %+v got %+v", want, files)
+	}
+
+	excluded, err := idx.Ref.ExcludedFiles()
+	if err != nil {
+		t.Fatalf("Could not read excluded files: %s", err)
+	}
+	got := make(map[string]string)
+	for _, ex := range excluded {
+		got[ex.Filename] = ex.Reason
+	}
+	want := map[string]string{"logo.png": reasonNotText}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v got %+v", want, got)
+	}
+}
+
+func TestSetRef(t *testing.T) {
+	idx := buildTestIndex(t, map[string]string{
+		"plugin.php": "<?php\n",
+	})
+
+	if err := idx.Ref.SetRef("tags/1.0", "2045123"); err != nil {
+		t.Fatalf("Could not set ref: %s", err)
+	}
+
+	ref, err := Read(idx.Ref.Dir())
+	if err != nil {
+		t.Fatalf("Could not read index: %s", err)
+	}
+	if ref.Ref != "tags/1.0" || ref.Commit != "2045123" || ref.Version != "1.0" {
+		t.Errorf("Expected %+v got %+v", idx.Ref, ref)
+	}
+}
+
+func TestDestroyAcquired(t *testing.T) {
+	idx := buildTestIndex(t, map[string]string{
+		"plugin.php": "<?php\n",
+	})
+	dir := idx.Ref.Dir()
+
+	if !idx.Acquire() {
+		t.Fatalf("Expected the index to be acquired")
+	}
+	if err := idx.Destroy(); err != nil {
+		t.Fatalf("Could not destroy index: %s", err)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("Expected the files of an acquired index to be kept got %s", err)
+	}
+	if idx.Acquire() {
+		t.Errorf("Expected a destroyed index not to be acquired")
+	}
+
+	if err := idx.Release(); err != nil {
+		t.Fatalf("Could not release index: %s", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected the files of a released index to be removed got %v", err)
+	}
+}
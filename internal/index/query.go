@@ -0,0 +1,577 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	goregexp "regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/wpdirectory/wpdir/internal/codesearch/index"
+	"github.com/wpdirectory/wpdir/internal/codesearch/regexp"
+)
+
+const (
+	// ScopeFile evaluates a query against each file on its own
+	ScopeFile = "file"
+	// ScopeExtension evaluates a query against all files of an Extension,
+	// so terms may match in different files
+	ScopeExtension = "extension"
+)
+
+// queryOp is the operation performed by a node of a BoolQuery
+type queryOp int
+
+const (
+	opTerm queryOp = iota
+	opAnd
+	opOr
+	opNot
+)
+
+// queryNode is a node in the expression tree of a BoolQuery
+type queryNode struct {
+	op   queryOp
+	term int
+	subs []*queryNode
+}
+
+// BoolQuery combines regular expressions with AND, OR and NOT
+// Terms are bare words or double quoted strings, adjacent terms are ANDed
+// and parentheses group, e.g.
+//
+//	"add_action\('wp_ajax_nopriv_" AND NOT check_ajax_referer
+type BoolQuery struct {
+	// Terms are the regular expressions in the order they appear
+	Terms []string
+	// positive marks Terms which are not negated, their matches are
+	// reported for files satisfying the query
+	positive []bool
+	root     *queryNode
+}
+
+// ParseQuery parses a boolean query
+func ParseQuery(input string) (*BoolQuery, error) {
+	toks, err := lexQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+
+	p := &queryParser{
+		toks: toks,
+		q:    &BoolQuery{},
+	}
+	root, err := p.parseOr(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %s", p.toks[p.pos])
+	}
+	p.q.root = root
+
+	return p.q, nil
+}
+
+// queryToken is a keyword, parenthesis or term within a query
+type queryToken struct {
+	text string
+	term bool
+}
+
+func (t queryToken) String() string {
+	if t.term {
+		return fmt.Sprintf("term %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lexQuery splits a query into tokens
+// Within quoted terms \" is an escaped quote, other escapes are kept as
+// they are part of the regular expression.
+func lexQuery(input string) ([]queryToken, error) {
+	var toks []queryToken
+	s := input
+	for {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		if s == "" {
+			return toks, nil
+		}
+
+		switch s[0] {
+		case '(', ')':
+			toks = append(toks, queryToken{text: s[:1]})
+			s = s[1:]
+		case '"':
+			var b strings.Builder
+			i := 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					if s[i+1] != '"' {
+						b.WriteByte('\\')
+					}
+					i++
+				}
+				b.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, fmt.Errorf("unterminated quoted term")
+			}
+			if b.Len() == 0 {
+				return nil, fmt.Errorf("empty quoted term")
+			}
+			toks = append(toks, queryToken{text: b.String(), term: true})
+			s = s[i+1:]
+		default:
+			end := strings.IndexFunc(s, func(r rune) bool {
+				return unicode.IsSpace(r) || r == '(' || r == ')'
+			})
+			if end < 0 {
+				end = len(s)
+			}
+			word := s[:end]
+			switch word {
+			case "AND", "OR", "NOT":
+				toks = append(toks, queryToken{text: word})
+			default:
+				toks = append(toks, queryToken{text: word, term: true})
+			}
+			s = s[end:]
+		}
+	}
+}
+
+// queryParser builds the expression tree of a BoolQuery
+//
+//	or    = and { "OR" and }
+//	and   = unary { ["AND"] unary }
+//	unary = "NOT" unary | "(" or ")" | term
+type queryParser struct {
+	toks []queryToken
+	pos  int
+	q    *BoolQuery
+}
+
+func (p *queryParser) peek(text string) bool {
+	return p.pos < len(p.toks) && !p.toks[p.pos].term && p.toks[p.pos].text == text
+}
+
+func (p *queryParser) parseOr(neg bool) (*queryNode, error) {
+	n, err := p.parseAnd(neg)
+	if err != nil {
+		return nil, err
+	}
+	if !p.peek("OR") {
+		return n, nil
+	}
+
+	or := &queryNode{op: opOr, subs: []*queryNode{n}}
+	for p.peek("OR") {
+		p.pos++
+		n, err := p.parseAnd(neg)
+		if err != nil {
+			return nil, err
+		}
+		or.subs = append(or.subs, n)
+	}
+	return or, nil
+}
+
+func (p *queryParser) parseAnd(neg bool) (*queryNode, error) {
+	n, err := p.parseUnary(neg)
+	if err != nil {
+		return nil, err
+	}
+
+	and := &queryNode{op: opAnd, subs: []*queryNode{n}}
+	for p.pos < len(p.toks) && !p.peek("OR") && !p.peek(")") {
+		if p.peek("AND") {
+			p.pos++
+		}
+		n, err := p.parseUnary(neg)
+		if err != nil {
+			return nil, err
+		}
+		and.subs = append(and.subs, n)
+	}
+	if len(and.subs) == 1 {
+		return and.subs[0], nil
+	}
+	return and, nil
+}
+
+func (p *queryParser) parseUnary(neg bool) (*queryNode, error) {
+	if p.pos == len(p.toks) {
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+
+	tok := p.toks[p.pos]
+	p.pos++
+	switch {
+	case tok.term:
+		if _, err := regexp.Compile(tok.text); err != nil {
+			return nil, fmt.Errorf("invalid term %q: %s", tok.text, err)
+		}
+		p.q.Terms = append(p.q.Terms, tok.text)
+		p.q.positive = append(p.q.positive, !neg)
+		return &queryNode{op: opTerm, term: len(p.q.Terms) - 1}, nil
+	case tok.text == "NOT":
+		n, err := p.parseUnary(!neg)
+		if err != nil {
+			return nil, err
+		}
+		return &queryNode{op: opNot, subs: []*queryNode{n}}, nil
+	case tok.text == "(":
+		n, err := p.parseOr(neg)
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return n, nil
+	default:
+		return nil, fmt.Errorf("unexpected %s", tok)
+	}
+}
+
+// trigrams combines the trigram queries of the terms
+// Negated terms can not narrow down the files, so match everything.
+func (n *queryNode) trigrams(qs []*index.Query) *index.Query {
+	switch n.op {
+	case opTerm:
+		return qs[n.term]
+	case opAnd, opOr:
+		q := n.subs[0].trigrams(qs)
+		for _, sub := range n.subs[1:] {
+			if n.op == opAnd {
+				q = q.And(sub.trigrams(qs))
+			} else {
+				q = q.Or(sub.trigrams(qs))
+			}
+		}
+		return q
+	}
+	return &index.Query{Op: index.QAll}
+}
+
+// eval evaluates the expression, calling match to find whether a term
+// matches. Terms are only matched when needed to decide the result.
+func (n *queryNode) eval(match func(term int) (bool, error)) (bool, error) {
+	switch n.op {
+	case opTerm:
+		return match(n.term)
+	case opNot:
+		ok, err := n.subs[0].eval(match)
+		return !ok, err
+	case opAnd:
+		for _, sub := range n.subs {
+			if ok, err := sub.eval(match); !ok || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	case opOr:
+		for _, sub := range n.subs {
+			if ok, err := sub.eval(match); ok || err != nil {
+				return ok, err
+			}
+		}
+	}
+	return false, nil
+}
+
+// compile returns the regular expression for each term and the combined
+// trigram query used to filter candidate files
+func (q *BoolQuery) compile(ignoreCase bool) ([]*regexp.Regexp, *index.Query, error) {
+	res := make([]*regexp.Regexp, len(q.Terms))
+	qs := make([]*index.Query, len(q.Terms))
+	for i, term := range q.Terms {
+		re, err := regexp.Compile(GetRegexpPattern(term, ignoreCase))
+		if err != nil {
+			return nil, nil, err
+		}
+		res[i] = re
+		qs[i] = index.RegexpQuery(re.Syntax)
+	}
+
+	return res, q.root.trigrams(qs), nil
+}
+
+// Analyze computes the trigram query used to filter files for the query
+func (q *BoolQuery) Analyze(ignoreCase bool) (*Analysis, error) {
+	_, tq, err := q.compile(ignoreCase)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAnalysis(tq), nil
+}
+
+// queryKey identifies the matches of a term within a file
+type queryKey struct {
+	file uint32
+	term int
+}
+
+// querySearch holds the state of a BoolQuery search within an Index
+type querySearch struct {
+	n      *Index
+	q      *BoolQuery
+	res    []*regexp.Regexp
+	spans  []*goregexp.Regexp
+	cfs    []*commentFilter
+	nctx   int
+	g      grepper
+	cache  map[queryKey][]*Match
+	opened map[uint32]bool
+}
+
+// grep returns the matches of a term within a file. Only the first match
+// is found for negated terms, as their lines are never reported.
+func (s *querySearch) grep(file uint32, term int) ([]*Match, error) {
+	k := queryKey{file, term}
+	if matches, ok := s.cache[k]; ok {
+		return matches, nil
+	}
+
+	name := s.n.idx.Name(file)
+	s.g.filter = nil
+	if s.cfs != nil {
+		s.g.filter = s.cfs[term].forFile(name)
+	}
+
+	var matches []*Match
+	s.opened[file] = true
+	if err := s.g.grep2File(filepath.Join(s.n.Ref.dir, "raw", name), s.res[term], s.nctx,
+		func(line []byte, lineno int, before [][]byte, after [][]byte) (bool, error) {
+			start, end := matchSpan(s.spans[term], line)
+			matches = append(matches, &Match{
+				Line:       string(line),
+				LineNumber: lineno,
+				Before:     toStrings(before),
+				After:      toStrings(after),
+				Start:      start,
+				End:        end,
+			})
+
+			if len(matches) > matchLimit {
+				return false, fmt.Errorf("search exceeds limit on matches: %d", matchLimit)
+			}
+
+			return s.q.positive[term], nil
+		}); err != nil {
+		return nil, err
+	}
+
+	s.cache[k] = matches
+	return matches, nil
+}
+
+// collect returns the matching lines of all positive terms in a file,
+// ordered by line number
+func (s *querySearch) collect(file uint32) ([]*Match, error) {
+	var matches []*Match
+	seen := make(map[int]bool)
+	for term, pos := range s.q.positive {
+		if !pos {
+			continue
+		}
+		ms, err := s.grep(file, term)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range ms {
+			if !seen[m.LineNumber] {
+				seen[m.LineNumber] = true
+				matches = append(matches, m)
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].LineNumber < matches[j].LineNumber
+	})
+	return matches, nil
+}
+
+// searchQuery searches the Index using a BoolQuery
+// At file scope each file must satisfy the query, at extension scope the
+// Extension as a whole must, and the matches of every file are returned.
+func (n *Index) searchQuery(ctx context.Context, input string, opt *SearchOptions) (*SearchResponse, error) {
+	startedAt := time.Now()
+
+	q, err := ParseQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	res, tq, err := q.compile(opt.IgnoreCase)
+	if err != nil {
+		return nil, err
+	}
+
+	var fre *regexp.Regexp
+	if opt.FileRegexp != "" {
+		fre, err = regexp.Compile(opt.FileRegexp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s := &querySearch{
+		n:      n,
+		q:      q,
+		res:    res,
+		nctx:   int(opt.LinesOfContext),
+		cache:  make(map[queryKey][]*Match),
+		opened: make(map[uint32]bool),
+	}
+
+	s.spans = make([]*goregexp.Regexp, len(q.Terms))
+	for i, term := range q.Terms {
+		s.spans[i], err = goregexp.Compile(GetRegexpPattern(term, opt.IgnoreCase))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opt.IgnoreComments {
+		s.cfs = make([]*commentFilter, len(q.Terms))
+		for i, term := range q.Terms {
+			s.cfs[i], err = newCommentFilter(GetRegexpPattern(term, opt.IgnoreCase))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// candidates returns the files which may match the trigram query
+	candidates := func(tq *index.Query) []uint32 {
+		var files []uint32
+		for _, file := range n.idx.PostingQuery(tq) {
+			if fre != nil && fre.MatchString(n.idx.Name(file), true, true) < 0 {
+				continue
+			}
+			files = append(files, file)
+		}
+		return files
+	}
+
+	var files []uint32
+	switch opt.Scope {
+	case "", ScopeFile:
+		for _, file := range candidates(tq) {
+			if ctx.Err() != nil {
+				break
+			}
+			ok, err := q.root.eval(func(term int) (bool, error) {
+				matches, err := s.grep(file, term)
+				return len(matches) > 0, err
+			})
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				files = append(files, file)
+			}
+		}
+	case ScopeExtension:
+		termFiles := make(map[int][]uint32)
+		ok, err := q.root.eval(func(term int) (bool, error) {
+			matched := false
+			for _, file := range candidates(index.RegexpQuery(res[term].Syntax)) {
+				if ctx.Err() != nil {
+					return false, ctx.Err()
+				}
+				matches, err := s.grep(file, term)
+				if err != nil {
+					return false, err
+				}
+				if len(matches) > 0 {
+					matched = true
+					termFiles[term] = append(termFiles[term], file)
+					if !q.positive[term] {
+						break
+					}
+				}
+			}
+			return matched, nil
+		})
+		if err != nil && err != ctx.Err() {
+			return nil, err
+		}
+		if ok {
+			seen := make(map[uint32]bool)
+			for term, pos := range q.positive {
+				if !pos {
+					continue
+				}
+				if _, done := termFiles[term]; !done {
+					// Not needed to decide the result, find any matches now
+					for _, file := range candidates(index.RegexpQuery(res[term].Syntax)) {
+						termFiles[term] = append(termFiles[term], file)
+					}
+				}
+				for _, file := range termFiles[term] {
+					if !seen[file] {
+						seen[file] = true
+						files = append(files, file)
+					}
+				}
+			}
+			sort.Slice(files, func(i, j int) bool { return files[i] < files[j] })
+		}
+	default:
+		return nil, fmt.Errorf("unknown query scope: %s", opt.Scope)
+	}
+
+	var (
+		results          []*FileMatch
+		filesFound       int
+		matchesCollected int
+	)
+	for _, file := range files {
+		if ctx.Err() != nil {
+			break
+		}
+
+		matches, err := s.collect(file)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 && opt.Scope == ScopeExtension {
+			continue
+		}
+
+		filesFound++
+		if filesFound <= opt.Offset || (opt.Limit > 0 && len(results) >= opt.Limit) {
+			continue
+		}
+
+		matchesCollected += len(matches)
+		if matchesCollected > matchLimit {
+			return nil, fmt.Errorf("search exceeds limit on matches: %d", matchLimit)
+		}
+
+		results = append(results, &FileMatch{
+			Filename: n.idx.Name(file),
+			Matches:  matches,
+		})
+	}
+
+	return &SearchResponse{
+		Matches:        results,
+		FilesWithMatch: filesFound,
+		FilesOpened:    len(s.opened),
+		Duration:       time.Now().Sub(startedAt),
+		Version:        n.Ref.Version,
+	}, ctx.Err()
+}
@@ -0,0 +1,170 @@
+package index
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		input    string
+		terms    []string
+		positive []bool
+	}{
+		{`check_ajax_referer`, []string{`check_ajax_referer`}, []bool{true}},
+		{
+			`"add_action\('wp_ajax_nopriv_" AND NOT check_ajax_referer`,
+			[]string{`add_action\('wp_ajax_nopriv_`, `check_ajax_referer`},
+			[]bool{true, false},
+		},
+		{
+			`unserialize (NOT "maybe_\"unserialize" OR eval)`,
+			[]string{`unserialize`, `maybe_"unserialize`, `eval`},
+			[]bool{true, false, true},
+		},
+		{
+			`NOT (wp_verify_nonce OR NOT current_user_can)`,
+			[]string{`wp_verify_nonce`, `current_user_can`},
+			[]bool{false, true},
+		},
+	}
+
+	for _, test := range tests {
+		q, err := ParseQuery(test.input)
+		if err != nil {
+			t.Fatalf("Could not parse %s: %s", test.input, err)
+		}
+		if !reflect.DeepEqual(q.Terms, test.terms) || !reflect.DeepEqual(q.positive, test.positive) {
+			t.Errorf("Expected %q %v got %q %v", test.terms, test.positive, q.Terms, q.positive)
+		}
+	}
+
+	invalid := []string{``, `abc AND`, `(abc`, `abc)`, `"abc`, `NOT`, `OR abc`, `"(unclosed"`}
+	for _, input := range invalid {
+		if _, err := ParseQuery(input); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
+
+func TestBoolQueryAnalyze(t *testing.T) {
+	tests := []struct {
+		input      string
+		unfiltered bool
+	}{
+		{`abcd AND NOT efgh`, false},
+		{`abcd OR efgh`, false},
+		{`NOT abcd`, true},
+		{`abcd OR NOT efgh`, true},
+		{`abcd (efgh OR NOT ijkl)`, false},
+	}
+
+	for _, test := range tests {
+		a, err := AnalyzeInput(test.input, ModeQuery, false)
+		if err != nil {
+			t.Fatalf("Could not analyze %s: %s", test.input, err)
+		}
+		if a.Unfiltered() != test.unfiltered {
+			t.Errorf("Expected unfiltered %t got %t for %s (%s)", test.unfiltered, a.Unfiltered(), test.input, a.Query)
+		}
+	}
+}
+
+func buildTestIndex(t *testing.T, files map[string]string) *Index {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "wpdir-index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	ref, _, err := BuildFromZip(&IndexOptions{}, buf.Bytes(), filepath.Join(dir, "idx"), "test", "1.0")
+	if err != nil {
+		t.Fatalf("Could not build index: %s", err)
+	}
+	idx, err := ref.Open()
+	if err != nil {
+		t.Fatalf("Could not open index: %s", err)
+	}
+	t.Cleanup(func() { idx.Close() })
+
+	return idx
+}
+
+func TestSearchQuery(t *testing.T) {
+	idx := buildTestIndex(t, map[string]string{
+		"ajax.php":  "<?php\nadd_action('wp_ajax_nopriv_save', 'save');\nfunction save() {}\n",
+		"safe.php":  "<?php\nadd_action('wp_ajax_nopriv_load', 'load');\ncheck_ajax_referer('load');\n",
+		"nonce.php": "<?php\n// check_ajax_referer\n",
+	})
+
+	tests := []struct {
+		input string
+		scope string
+		want  map[string][]int
+	}{
+		{
+			input: `"add_action\('wp_ajax_nopriv_" AND NOT check_ajax_referer`,
+			want:  map[string][]int{"ajax.php": {2}},
+		},
+		{
+			input: `wp_ajax_nopriv_ check_ajax_referer`,
+			want:  map[string][]int{"safe.php": {2, 3}},
+		},
+		{
+			input: `wp_ajax_nopriv_save OR check_ajax_referer`,
+			want:  map[string][]int{"ajax.php": {2}, "safe.php": {3}, "nonce.php": {2}},
+		},
+		{
+			input: `wp_ajax_nopriv_save AND check_ajax_referer`,
+			scope: ScopeExtension,
+			want:  map[string][]int{"ajax.php": {2}, "safe.php": {3}, "nonce.php": {2}},
+		},
+		{
+			input: `wp_ajax_nopriv_ AND NOT check_ajax_referer`,
+			scope: ScopeExtension,
+			want:  map[string][]int{},
+		},
+	}
+
+	for _, test := range tests {
+		resp, err := idx.Search(context.Background(), test.input, "test", &SearchOptions{
+			Mode:  ModeQuery,
+			Scope: test.scope,
+		})
+		if err != nil {
+			t.Fatalf("Could not search %s: %s", test.input, err)
+		}
+		if resp.Version != "1.0" {
+			t.Errorf("Expected version %+v got %+v", "1.0", resp.Version)
+		}
+
+		got := make(map[string][]int)
+		for _, fm := range resp.Matches {
+			for _, m := range fm.Matches {
+				got[fm.Filename] = append(got[fm.Filename], m.LineNumber)
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Expected %+v got %+v for %s", test.want, got, test.input)
+		}
+	}
+}
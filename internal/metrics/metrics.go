@@ -11,6 +11,8 @@ var (
 	SearchDuration prometheus.Histogram
 	// SearchCount contains a counter of searches.
 	SearchCount prometheus.Counter
+	// SearchErrors contains a counter of extensions which failed to be searched.
+	SearchErrors prometheus.Counter
 )
 
 // Setup creates metrics ready for use
@@ -35,4 +37,11 @@ func Setup() {
 		Help:      "Total number of searches",
 	})
 	prometheus.MustRegister(SearchCount)
+
+	SearchErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "wpdir",
+		Name:      "search_errors",
+		Help:      "Total number of extensions which failed to be searched",
+	})
+	prometheus.MustRegister(SearchErrors)
 }
@@ -1,6 +1,8 @@
 package repo
 
 import (
+	"context"
+	"log"
 	"sync"
 
 	"github.com/wpdirectory/wpdir/internal/filestats"
@@ -43,6 +45,7 @@ type Extension struct {
 	DonateLink       string       `json:"donate_link,omitempty"`
 	Status           status       `json:"status,omitempty"`
 	index            *index.Index
+	history          []*index.Index
 	Stats            *filestats.Stats `json:"stats,omitempty"`
 	sync.RWMutex
 }
@@ -91,18 +94,80 @@ func (e *Extension) GetStatus() string {
 	}
 }
 
-// SwapIndexes switches to a new index
-func (e *Extension) SwapIndexes(idx *index.Index) error {
+const (
+	// VersionLatest searches only the current index of an Extension
+	VersionLatest = "latest"
+	// VersionAll searches every retained index of an Extension
+	VersionAll = "all"
+)
+
+// SwapIndexes switches to a new index, keeping the indexes of up to keep
+// versions in total. Older indexes, and those built from the same version
+// as the new index, are destroyed. The new index is in use either way, so
+// failures to destroy old indexes are only logged.
+func (e *Extension) SwapIndexes(idx *index.Index, keep int) {
 	e.Lock()
 	defer e.Unlock()
 
-	oldIdx := e.index
+	if keep < 1 {
+		keep = 1
+	}
+
+	var retained, removed []*index.Index
+	if e.index != nil {
+		retained = append(retained, e.index)
+	}
+	retained = append(retained, e.history...)
 	e.index = idx
 
-	if oldIdx != nil {
-		return oldIdx.Destroy()
+	e.history = e.history[:0]
+	for _, old := range retained {
+		sameVersion := old.Ref.Version != "" && old.Ref.Version == idx.Ref.Version
+		if sameVersion || len(e.history) >= keep-1 {
+			removed = append(removed, old)
+			continue
+		}
+		e.history = append(e.history, old)
 	}
 
+	for _, old := range removed {
+		if err := old.Destroy(); err != nil {
+			log.Printf("Failed destroying %s index %s: %s\n", e.Slug, old.Ref.Dir(), err)
+		}
+	}
+}
+
+// IndexedVersions returns the versions which can be searched, newest first
+func (e *Extension) IndexedVersions() []string {
+	e.RLock()
+	defer e.RUnlock()
+
+	var versions []string
+	for _, idx := range e.indexes(VersionAll) {
+		versions = append(versions, idx.Ref.Version)
+	}
+	return versions
+}
+
+// indexes returns the indexes selected by version, which may be
+// VersionLatest (or empty), VersionAll or a specific version
+func (e *Extension) indexes(version string) []*index.Index {
+	if e.index == nil {
+		return nil
+	}
+
+	switch version {
+	case "", VersionLatest:
+		return []*index.Index{e.index}
+	case VersionAll:
+		return append([]*index.Index{e.index}, e.history...)
+	}
+
+	for _, idx := range append([]*index.Index{e.index}, e.history...) {
+		if idx.Ref.Version == version {
+			return []*index.Index{idx}
+		}
+	}
 	return nil
 }
 
@@ -116,8 +181,86 @@ func (e *Extension) Dir() string {
 
 // Search performs a basic search on the current index using the supplied pattern
 // and the options.
-func (e *Extension) Search(pat, slug string, opt *index.SearchOptions) (*index.SearchResponse, error) {
+func (e *Extension) Search(ctx context.Context, pat, slug string, opt *index.SearchOptions) (*index.SearchResponse, error) {
+	e.RLock()
+	defer e.RUnlock()
+	return e.index.Search(ctx, pat, slug, opt)
+}
+
+// SearchVersions searches the indexes selected by version, newest first
+// A response is returned for each index searched.
+func (e *Extension) SearchVersions(ctx context.Context, pat, slug, version string, opt *index.SearchOptions) ([]*index.SearchResponse, error) {
+	e.RLock()
+	defer e.RUnlock()
+
+	var resps []*index.SearchResponse
+	for _, idx := range e.indexes(version) {
+		resp, err := idx.Search(ctx, pat, slug, opt)
+		if resp != nil {
+			resps = append(resps, resp)
+		}
+		if err != nil {
+			return resps, err
+		}
+	}
+	return resps, nil
+}
+
+// VersionDir returns the dir of the index of a version, VersionLatest or
+// an empty version returns the current index dir
+func (e *Extension) VersionDir(version string) (string, bool) {
+	ref, ok := e.VersionRef(version)
+	if !ok {
+		return "", false
+	}
+	return ref.Dir(), true
+}
+
+// VersionFiles returns the indexed files and IndexRef of a version
+// VersionLatest or an empty version returns the current index files.
+func (e *Extension) VersionFiles(version string) ([]string, *index.IndexRef, bool) {
 	e.RLock()
 	defer e.RUnlock()
-	return e.index.Search(pat, slug, opt)
+
+	if version == VersionAll {
+		return nil, nil, false
+	}
+	idxs := e.indexes(version)
+	if len(idxs) == 0 {
+		return nil, nil, false
+	}
+	return idxs[0].Files(), idxs[0].Ref, true
+}
+
+// AcquireVersion returns the index of a version, VersionLatest or an empty
+// version returns the current index. Its files are kept until it is
+// released, even if it is swapped out meanwhile.
+func (e *Extension) AcquireVersion(version string) (*index.Index, bool) {
+	e.RLock()
+	defer e.RUnlock()
+
+	if version == VersionAll {
+		return nil, false
+	}
+	idxs := e.indexes(version)
+	if len(idxs) == 0 || !idxs[0].Acquire() {
+		return nil, false
+	}
+	return idxs[0], true
+}
+
+// VersionRef returns the IndexRef of a version, VersionLatest or an empty
+// version returns the current IndexRef
+func (e *Extension) VersionRef(version string) (*index.IndexRef, bool) {
+	e.RLock()
+	defer e.RUnlock()
+
+	if version == VersionAll {
+		return nil, false
+	}
+	idxs := e.indexes(version)
+	if len(idxs) == 0 {
+		return nil, false
+	}
+	return idxs[0].Ref, true
 }
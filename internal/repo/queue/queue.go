@@ -0,0 +1,466 @@
+package queue
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// Pending lists the Items waiting to be processed or retried
+	Pending = "update_pending"
+	// Dead lists the Items which failed on every attempt
+	Dead = "update_dead"
+
+	// StateReady Items are waiting for a worker
+	StateReady = "ready"
+	// StateInFlight Items are being processed
+	StateInFlight = "in_flight"
+	// StateDelayed Items are waiting out the backoff of a failed attempt
+	StateDelayed = "delayed"
+	// StateDead Items failed on every attempt
+	StateDead = "dead"
+
+	maxAttempts     = 5
+	baseBackoff     = time.Minute
+	promoteInterval = time.Second
+)
+
+// Item is an Extension waiting to be updated
+type Item struct {
+	Repo        string    `json:"repo"`
+	Slug        string    `json:"slug"`
+	Revision    int       `json:"revision"`
+	Attempts    int       `json:"attempts"`
+	Err         string    `json:"error,omitempty"`
+	Queued      time.Time `json:"queued"`
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// Key identifies the Item, an Extension has at most one Item in each list
+func (it *Item) Key() string {
+	return Key(it.Repo, it.Slug)
+}
+
+// Key returns the key of an Extension's Item
+func Key(repo, slug string) string {
+	return repo + "/" + slug
+}
+
+// Status is a copy of a Pending Item with its state
+type Status struct {
+	Item
+	State string `json:"state"`
+}
+
+// Store persists Items in the Pending and Dead lists
+type Store interface {
+	Save(list string, items ...*Item) error
+	Delete(list string, keys ...string) error
+	// Move deletes an Item from one list and saves it in another at once
+	Move(from, to string, it *Item) error
+	Items(list string) ([]*Item, error)
+}
+
+// Queue holds the Extensions waiting to be updated, keeping them in a Store
+// so they survive restarts. Failed updates are retried with exponential
+// backoff until maxAttempts is reached, when they move to the Dead list.
+type Queue struct {
+	store       Store
+	items       map[string]*entry
+	ready       []string
+	delayed     map[string]bool
+	dead        map[string]bool
+	counts      map[string]int
+	paused      bool
+	maxAttempts int
+	backoff     time.Duration
+	now         func() time.Time
+	cond        *sync.Cond
+	sync.Mutex
+}
+
+// entry tracks the state of a Pending Item
+type entry struct {
+	item *Item
+	// ready is set while the Item is waiting in the ready list
+	ready bool
+	// inFlight is set while the Item is being processed
+	inFlight bool
+	// requeue is set when a newer revision was added while in flight
+	requeue bool
+}
+
+// New returns a Queue holding the Items already in the Store
+func New(store Store) (*Queue, error) {
+	q := newQueue(store)
+	if err := q.load(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func newQueue(store Store) *Queue {
+	q := &Queue{
+		store:       store,
+		items:       make(map[string]*entry),
+		delayed:     make(map[string]bool),
+		dead:        make(map[string]bool),
+		counts:      make(map[string]int),
+		maxAttempts: maxAttempts,
+		backoff:     baseBackoff,
+		now:         time.Now,
+	}
+	q.cond = sync.NewCond(&q.Mutex)
+	return q
+}
+
+// load adds the Items in the Store, those which are due are ready in the
+// order they were queued
+func (q *Queue) load() error {
+	dead, err := q.store.Items(Dead)
+	if err != nil {
+		return err
+	}
+	for _, it := range dead {
+		q.dead[it.Key()] = true
+	}
+
+	pending, err := q.store.Items(Pending)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].Queued.Before(pending[j].Queued) })
+
+	now := q.now()
+	for _, it := range pending {
+		key := it.Key()
+		q.items[key] = &entry{item: it}
+		q.counts[it.Repo]++
+		if it.NextAttempt.After(now) {
+			q.delayed[key] = true
+		} else {
+			q.push(key)
+		}
+	}
+
+	return nil
+}
+
+// Add queues an update of an Extension to a revision
+func (q *Queue) Add(repo, slug string, revision int) error {
+	return q.AddAll(repo, map[string]int{slug: revision})
+}
+
+// AddAll queues updates of Extensions, given by slug, to their revisions
+// in a single write. An Extension already queued for the same or a later
+// revision is left alone, otherwise it is moved on to the new revision and
+// retried straight away, as the new revision may fix a failing update.
+func (q *Queue) AddAll(repo string, revisions map[string]int) error {
+	return q.add(repo, revisions, false)
+}
+
+// Force queues updates of Extensions like AddAll, but Extensions already
+// queued are updated again even if the revision is not newer, clearing their
+// failed attempts. Items in flight are processed once more when they finish.
+func (q *Queue) Force(repo string, revisions map[string]int) error {
+	return q.add(repo, revisions, true)
+}
+
+func (q *Queue) add(repo string, revisions map[string]int, force bool) error {
+	q.Lock()
+	defer q.Unlock()
+
+	now := q.now()
+	var save []*Item
+	var revive []string
+	var ready []string
+
+	slugs := make([]string, 0, len(revisions))
+	for slug := range revisions {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	for _, slug := range slugs {
+		rev := revisions[slug]
+		key := Key(repo, slug)
+
+		if e, ok := q.items[key]; ok {
+			if rev <= e.item.Revision {
+				if !force || (e.ready && e.item.Attempts == 0) {
+					continue
+				}
+				rev = e.item.Revision
+			}
+			it := *e.item
+			it.Revision = rev
+			it.Attempts = 0
+			it.Err = ""
+			it.NextAttempt = now
+			save = append(save, &it)
+			continue
+		}
+
+		save = append(save, &Item{
+			Repo:        repo,
+			Slug:        slug,
+			Revision:    rev,
+			Queued:      now,
+			NextAttempt: now,
+		})
+		if q.dead[key] {
+			revive = append(revive, key)
+		}
+	}
+
+	if len(save) == 0 {
+		return nil
+	}
+	if err := q.store.Save(Pending, save...); err != nil {
+		return err
+	}
+	if len(revive) > 0 {
+		if err := q.store.Delete(Dead, revive...); err != nil {
+			return err
+		}
+		for _, key := range revive {
+			delete(q.dead, key)
+		}
+	}
+
+	for _, it := range save {
+		key := it.Key()
+		e, ok := q.items[key]
+		if !ok {
+			e = &entry{}
+			q.items[key] = e
+			q.counts[repo]++
+		}
+		e.item = it
+		delete(q.delayed, key)
+
+		switch {
+		case e.inFlight:
+			e.requeue = true
+		case !e.ready:
+			q.push(key)
+			ready = append(ready, key)
+		}
+	}
+	if len(ready) > 0 {
+		q.cond.Broadcast()
+	}
+
+	return nil
+}
+
+// Next waits for an Item to be ready, and the Queue to be running, and
+// returns a copy of it
+// The Item must be passed to Done once it has been processed.
+func (q *Queue) Next() *Item {
+	q.Lock()
+	defer q.Unlock()
+
+	for q.paused || len(q.ready) == 0 {
+		q.cond.Wait()
+	}
+	key := q.ready[0]
+	q.ready = q.ready[1:]
+
+	e := q.items[key]
+	e.ready = false
+	e.inFlight = true
+
+	it := *e.item
+	return &it
+}
+
+// Done records the result of processing an Item
+// Successful Items are removed, failed Items are retried after a backoff
+// which doubles with each attempt, or moved to the Dead list after the last.
+func (q *Queue) Done(it *Item, err error) error {
+	q.Lock()
+	defer q.Unlock()
+
+	key := it.Key()
+	e, ok := q.items[key]
+	if !ok || !e.inFlight {
+		return nil
+	}
+	e.inFlight = false
+
+	// A newer revision was added while processing, which is needed whether
+	// or not this attempt succeeded
+	if e.requeue {
+		e.requeue = false
+		q.push(key)
+		q.cond.Signal()
+		return nil
+	}
+
+	if err == nil {
+		q.remove(key)
+		return q.store.Delete(Pending, key)
+	}
+
+	failed := *e.item
+	failed.Attempts++
+	failed.Err = err.Error()
+
+	if failed.Attempts >= q.maxAttempts {
+		q.remove(key)
+		q.dead[key] = true
+		return q.store.Move(Pending, Dead, &failed)
+	}
+
+	failed.NextAttempt = q.now().Add(q.backoff << uint(failed.Attempts-1))
+	e.item = &failed
+	q.delayed[key] = true
+
+	return q.store.Save(Pending, &failed)
+}
+
+// Start processes Items with a number of workers, retrying them as they
+// become due
+func (q *Queue) Start(workers int, process func(it *Item) error) {
+	go func() {
+		for range time.Tick(promoteInterval) {
+			q.promote()
+		}
+	}()
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				it := q.Next()
+				if err := q.Done(it, process(it)); err != nil {
+					log.Printf("Failed saving update of %s: %s\n", it.Key(), err)
+				}
+			}
+		}()
+	}
+}
+
+// Len returns the number of Pending Items
+func (q *Queue) Len() int {
+	q.Lock()
+	defer q.Unlock()
+
+	return len(q.items)
+}
+
+// Pending returns the number of Pending Items of a Repo
+func (q *Queue) Pending(repo string) int {
+	q.Lock()
+	defer q.Unlock()
+
+	return q.counts[repo]
+}
+
+// Dead returns the Items which failed on every attempt
+func (q *Queue) Dead() ([]*Item, error) {
+	return q.store.Items(Dead)
+}
+
+// Items returns the Pending Items with their state, those in flight first,
+// then those ready in the order they will be processed, then those delayed
+// by their next attempt
+func (q *Queue) Items() []*Status {
+	q.Lock()
+	defer q.Unlock()
+
+	var inFlight, ready, delayed []*Status
+	for _, key := range q.ready {
+		ready = append(ready, &Status{Item: *q.items[key].item, State: StateReady})
+	}
+	for _, e := range q.items {
+		switch {
+		case e.inFlight:
+			inFlight = append(inFlight, &Status{Item: *e.item, State: StateInFlight})
+		case !e.ready:
+			delayed = append(delayed, &Status{Item: *e.item, State: StateDelayed})
+		}
+	}
+
+	sort.Slice(inFlight, func(i, j int) bool { return inFlight[i].Key() < inFlight[j].Key() })
+	sort.Slice(delayed, func(i, j int) bool {
+		if delayed[i].NextAttempt.Equal(delayed[j].NextAttempt) {
+			return delayed[i].Key() < delayed[j].Key()
+		}
+		return delayed[i].NextAttempt.Before(delayed[j].NextAttempt)
+	})
+
+	items := make([]*Status, 0, len(q.items))
+	items = append(items, inFlight...)
+	items = append(items, ready...)
+	return append(items, delayed...)
+}
+
+// Pause stops workers taking new Items, those in flight are finished
+// The Queue is running again after a restart.
+func (q *Queue) Pause() {
+	q.Lock()
+	defer q.Unlock()
+
+	q.paused = true
+}
+
+// Resume lets workers take Items again
+func (q *Queue) Resume() {
+	q.Lock()
+	defer q.Unlock()
+
+	q.paused = false
+	q.cond.Broadcast()
+}
+
+// Paused reports whether the workers are paused
+func (q *Queue) Paused() bool {
+	q.Lock()
+	defer q.Unlock()
+
+	return q.paused
+}
+
+// promote readies the delayed Items which are due
+func (q *Queue) promote() {
+	q.Lock()
+	defer q.Unlock()
+
+	now := q.now()
+	var due []string
+	for key := range q.delayed {
+		if !q.items[key].item.NextAttempt.After(now) {
+			due = append(due, key)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	sort.Strings(due)
+	for _, key := range due {
+		delete(q.delayed, key)
+		q.push(key)
+	}
+	q.cond.Broadcast()
+}
+
+// push adds an Item to the end of the ready list
+func (q *Queue) push(key string) {
+	q.items[key].ready = true
+	q.ready = append(q.ready, key)
+}
+
+// remove forgets a Pending Item
+func (q *Queue) remove(key string) {
+	e := q.items[key]
+	delete(q.items, key)
+	delete(q.delayed, key)
+	q.counts[e.item.Repo]--
+	if q.counts[e.item.Repo] == 0 {
+		delete(q.counts, e.item.Repo)
+	}
+}
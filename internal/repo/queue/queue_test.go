@@ -0,0 +1,229 @@
+package queue
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// testQueue returns a Queue over the store with a clock set by the test
+func testQueue(t *testing.T, store Store, now *time.Time) *Queue {
+	q := newQueue(store)
+	q.now = func() time.Time { return *now }
+	q.maxAttempts = 3
+	q.backoff = time.Minute
+	if err := q.load(); err != nil {
+		t.Fatalf("Could not load queue: %s", err)
+	}
+	return q
+}
+
+func TestQueueAdd(t *testing.T) {
+	now := time.Unix(1000, 0)
+	q := testQueue(t, newMemStore(), &now)
+
+	q.Add("plugins", "akismet", 10)
+	q.Add("themes", "twentyten", 10)
+	q.Add("plugins", "akismet", 9)
+	q.Add("plugins", "hello-dolly", 11)
+
+	if q.Len() != 3 || q.Pending("plugins") != 2 || q.Pending("themes") != 1 {
+		t.Errorf("Expected 3 pending, 2 plugins and 1 theme got %d, %d and %d", q.Len(), q.Pending("plugins"), q.Pending("themes"))
+	}
+
+	it := q.Next()
+	if it.Key() != "plugins/akismet" || it.Revision != 10 {
+		t.Errorf("Expected %+v got %+v", "plugins/akismet@10", it)
+	}
+
+	// A newer revision while in flight is processed again afterwards
+	q.Add("plugins", "akismet", 12)
+	if err := q.Done(it, nil); err != nil {
+		t.Fatalf("Could not finish: %s", err)
+	}
+
+	var keys []string
+	for q.Len() > 0 {
+		it := q.Next()
+		keys = append(keys, it.Key())
+		if it.Key() == "plugins/akismet" && it.Revision != 12 {
+			t.Errorf("Expected revision %d got %d", 12, it.Revision)
+		}
+		q.Done(it, nil)
+	}
+
+	expected := []string{"themes/twentyten", "plugins/hello-dolly", "plugins/akismet"}
+	if len(keys) != len(expected) {
+		t.Fatalf("Expected %+v got %+v", expected, keys)
+	}
+	for i := range keys {
+		if keys[i] != expected[i] {
+			t.Errorf("Expected %+v got %+v", expected, keys)
+		}
+	}
+	if q.Pending("plugins") != 0 {
+		t.Errorf("Expected no pending plugins got %d", q.Pending("plugins"))
+	}
+}
+
+func TestQueueRetry(t *testing.T) {
+	now := time.Unix(1000, 0)
+	store := newMemStore()
+	q := testQueue(t, store, &now)
+	failed := errors.New("download failed")
+
+	q.Add("plugins", "akismet", 10)
+
+	// Attempts are retried after 1 then 2 minutes
+	for attempt, wait := range []time.Duration{time.Minute, 2 * time.Minute} {
+		it := q.Next()
+		if it.Attempts != attempt {
+			t.Errorf("Expected %d attempts got %d", attempt, it.Attempts)
+		}
+		q.Done(it, failed)
+
+		saved := store.lists[Pending]["plugins/akismet"]
+		if saved.Attempts != attempt+1 || saved.Err != failed.Error() || !saved.NextAttempt.Equal(now.Add(wait)) {
+			t.Errorf("Unexpected saved item %+v", saved)
+		}
+
+		now = now.Add(wait - time.Second)
+		q.promote()
+		if len(q.ready) != 0 {
+			t.Errorf("Expected no ready items before the backoff got %+v", q.ready)
+		}
+		now = now.Add(time.Second)
+		q.promote()
+		if len(q.ready) != 1 {
+			t.Errorf("Expected a ready item after the backoff got %+v", q.ready)
+		}
+	}
+
+	// The last attempt dead letters the item
+	q.Done(q.Next(), failed)
+	dead, _ := q.Dead()
+	if q.Len() != 0 || len(store.lists[Pending]) != 0 || len(dead) != 1 || dead[0].Attempts != 3 {
+		t.Errorf("Expected a single dead item got %d pending and %+v", q.Len(), dead)
+	}
+
+	// Queueing a new revision revives it
+	q.Add("plugins", "akismet", 11)
+	dead, _ = q.Dead()
+	if q.Len() != 1 || len(dead) != 0 {
+		t.Errorf("Expected the item to be pending again got %d pending and %+v", q.Len(), dead)
+	}
+	if it := q.Next(); it.Revision != 11 || it.Attempts != 0 {
+		t.Errorf("Unexpected item %+v", it)
+	}
+}
+
+func TestQueueResume(t *testing.T) {
+	now := time.Unix(1000, 0)
+	store := newMemStore()
+	q := testQueue(t, store, &now)
+
+	q.Add("plugins", "first", 1)
+	now = now.Add(time.Second)
+	q.Add("plugins", "second", 1)
+	now = now.Add(time.Second)
+	q.Add("plugins", "failing", 1)
+	now = now.Add(time.Second)
+	q.Add("plugins", "done", 1)
+
+	// Process the first and last, fail one and leave one in flight
+	q.Done(q.Next(), nil)
+	inFlight := q.Next()
+	q.Done(q.Next(), errors.New("failed"))
+	q.Done(q.Next(), nil)
+
+	// Restarting resumes the unfinished items, in the order queued, with the
+	// failed item waiting out its backoff
+	resumed := testQueue(t, store, &now)
+	if resumed.Len() != 2 {
+		t.Fatalf("Expected 2 pending got %d", resumed.Len())
+	}
+	if it := resumed.Next(); it.Key() != inFlight.Key() {
+		t.Errorf("Expected %+v got %+v", inFlight.Key(), it.Key())
+	}
+	if len(resumed.ready) != 0 {
+		t.Errorf("Expected the failed item to be delayed got %+v", resumed.ready)
+	}
+
+	now = now.Add(time.Minute)
+	resumed.promote()
+	if it := resumed.Next(); it.Key() != "plugins/failing" || it.Attempts != 1 {
+		t.Errorf("Unexpected item %+v", it)
+	}
+}
+
+func TestQueueForce(t *testing.T) {
+	now := time.Unix(1000, 0)
+	store := newMemStore()
+	q := testQueue(t, store, &now)
+
+	q.Add("plugins", "akismet", 10)
+	q.Add("plugins", "hello-dolly", 10)
+	q.Add("plugins", "jetpack", 10)
+	q.Done(q.Next(), errors.New("failed"))
+	inFlight := q.Next()
+
+	states := make(map[string]string)
+	for _, it := range q.Items() {
+		states[it.Key()] = it.State
+	}
+	expected := map[string]string{
+		"plugins/akismet":     StateDelayed,
+		"plugins/hello-dolly": StateInFlight,
+		"plugins/jetpack":     StateReady,
+	}
+	for key, state := range expected {
+		if states[key] != state {
+			t.Errorf("Expected %+v got %+v", expected, states)
+		}
+	}
+
+	// Forcing keeps the revision but retries the failed item straight away
+	q.Force("plugins", map[string]int{"akismet": 9, "hello-dolly": 10, "jetpack": 10})
+	if it := q.Next(); it.Key() != "plugins/jetpack" {
+		t.Errorf("Expected %+v got %+v", "plugins/jetpack", it.Key())
+	}
+	if it := q.Next(); it.Key() != "plugins/akismet" || it.Revision != 10 || it.Attempts != 0 {
+		t.Errorf("Unexpected item %+v", it)
+	}
+
+	// The forced item in flight is processed once more
+	q.Done(inFlight, nil)
+	if it := q.Next(); it.Key() != inFlight.Key() {
+		t.Errorf("Expected %+v got %+v", inFlight.Key(), it.Key())
+	}
+}
+
+func TestQueuePause(t *testing.T) {
+	now := time.Unix(1000, 0)
+	q := testQueue(t, newMemStore(), &now)
+
+	q.Pause()
+	q.Add("plugins", "akismet", 10)
+
+	next := make(chan *Item)
+	go func() { next <- q.Next() }()
+
+	select {
+	case it := <-next:
+		t.Fatalf("Expected no item while paused got %+v", it)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if !q.Paused() {
+		t.Errorf("Expected the queue to be paused")
+	}
+
+	q.Resume()
+	select {
+	case it := <-next:
+		if it.Key() != "plugins/akismet" {
+			t.Errorf("Expected %+v got %+v", "plugins/akismet", it.Key())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected an item once resumed")
+	}
+}
@@ -0,0 +1,114 @@
+package queue
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/wpdirectory/wpdir/internal/db"
+)
+
+// dbStore keeps Items in the updates bucket of the DB
+type dbStore struct{}
+
+// NewDBStore returns a Store backed by the DB
+func NewDBStore() Store {
+	return dbStore{}
+}
+
+func (dbStore) Save(list string, items ...*Item) error {
+	values := make(map[string][]byte, len(items))
+	for _, it := range items {
+		b, err := json.Marshal(it)
+		if err != nil {
+			return err
+		}
+		values[it.Key()] = b
+	}
+	return db.SaveUpdates(list, values)
+}
+
+func (dbStore) Delete(list string, keys ...string) error {
+	return db.DeleteUpdates(list, keys)
+}
+
+func (dbStore) Move(from, to string, it *Item) error {
+	b, err := json.Marshal(it)
+	if err != nil {
+		return err
+	}
+	return db.MoveUpdate(from, to, it.Key(), b)
+}
+
+func (dbStore) Items(list string) ([]*Item, error) {
+	values, err := db.GetUpdates(list)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*Item, 0, len(values))
+	for _, b := range values {
+		var it Item
+		if err := json.Unmarshal(b, &it); err != nil {
+			return nil, err
+		}
+		items = append(items, &it)
+	}
+	return items, nil
+}
+
+// memStore keeps Items in memory, for tests and runs without a DB
+type memStore struct {
+	lists map[string]map[string]Item
+	sync.Mutex
+}
+
+// NewMemStore returns a Store which only keeps Items in memory
+func NewMemStore() Store {
+	return newMemStore()
+}
+
+func newMemStore() *memStore {
+	return &memStore{lists: map[string]map[string]Item{
+		Pending: make(map[string]Item),
+		Dead:    make(map[string]Item),
+	}}
+}
+
+func (s *memStore) Save(list string, items ...*Item) error {
+	s.Lock()
+	defer s.Unlock()
+	for _, it := range items {
+		s.lists[list][it.Key()] = *it
+	}
+	return nil
+}
+
+func (s *memStore) Delete(list string, keys ...string) error {
+	s.Lock()
+	defer s.Unlock()
+	for _, key := range keys {
+		delete(s.lists[list], key)
+	}
+	return nil
+}
+
+func (s *memStore) Move(from, to string, it *Item) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.lists[from], it.Key())
+	s.lists[to][it.Key()] = *it
+	return nil
+}
+
+func (s *memStore) Items(list string) ([]*Item, error) {
+	s.Lock()
+	defer s.Unlock()
+	var items []*Item
+	for _, it := range s.lists[list] {
+		it := it
+		items = append(items, &it)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Key() < items[j].Key() })
+	return items, nil
+}
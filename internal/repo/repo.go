@@ -7,7 +7,6 @@ import (
 	"bytes"
 	"io/ioutil"
 	"log"
-	"net/http"
 	"os"
 	"strings"
 	"sort"
@@ -17,65 +16,85 @@ import (
 	"time"
 	"unicode/utf8"
 
-	"github.com/wpdirectory/wpdir/internal/client"
 	"github.com/wpdirectory/wpdir/internal/config"
 	"github.com/wpdirectory/wpdir/internal/db"
 	"github.com/wpdirectory/wpdir/internal/filestats"
 	"github.com/wpdirectory/wpdir/internal/index"
+	"github.com/wpdirectory/wpdir/internal/source"
 	"github.com/wpdirectory/wpdir/internal/ulid"
-	"github.com/wpdirectory/wpdir/internal/utils"
 	"github.com/wpdirectory/wpdir/internal/tasks"
-	"github.com/wpdirectory/wporg"
+	"github.com/wpdirectory/wpdir/internal/webhook"
 	"github.com/wcharczuk/go-chart"
 ) 
 
-var (
-	archiveURL = "http://downloads.wordpress.org/%s/%s.latest-stable.zip?nostats=1"
-)
-
-// Repo holds data about the Plugins SVN Repo.
+// Repo holds data about a named Repository of Extensions.
 type Repo struct {
+	Name        string             `json:"name"`
 	ExtType     string             `json:"type"`
 	Revision    int                `json:"revision"`
 	Updated     time.Time          `json:"updated"`
 	Total       int                `json:"total"`
 	Closed      int                `json:"closed"`
-	UpdateQueue chan UpdateRequest `json:"-"`
 
 	List map[string]*Extension `json:"-"`
 	sync.RWMutex
 
-	log *log.Logger
-	cfg *config.Config
-	api *wporg.Client
+	log      *log.Logger
+	cfg      *config.Config
+	src      source.Source
+	onUpdate UpdateHook
+	// running is guarded by jobMu, as jobs may hold the Repo lock
+	running map[string]bool
+	jobMu   sync.Mutex
 }
 
+const (
+	// JobCheckChangelog queues the Extensions changed in the Source
+	JobCheckChangelog = "check-changelog"
+	// JobUpdateMeta refreshes the meta data of every Extension
+	JobUpdateMeta = "update-meta"
+)
+
+var (
+	// ErrUnknownJob is returned when starting a job which does not exist
+	ErrUnknownJob = errors.New("Job not recognized")
+	// ErrJobRunning is returned when starting a job which is already running
+	ErrJobRunning = errors.New("Job is already running")
+)
+
+// UpdateHook is called once the new index of an updated Extension is in use
+// oldVersion is the version of the Extension before the update.
+type UpdateHook func(repoName string, e *Extension, oldVersion string)
+
 // New returns a new Repo
-func New(c *config.Config, l *log.Logger, t string, rev int) *Repo {
-	// Setup HTTP Client
-	opt := func(c *wporg.Client) {
-		c.HTTPClient = client.GetAPI()
+// Its Extensions are stored in a DB bucket and index dir named after it.
+func New(c *config.Config, l *log.Logger, rc config.Repository, rev int) *Repo {
+	// Setup Source
+	src, err := source.New(rc.Source, rc.Type, c.Name+"/"+c.Version)
+	if err != nil {
+		l.Fatalf("Repo (%s) could not setup source: %s\n", rc.Name, err)
 	}
-	api := wporg.NewClient(opt)
 
-	repo := &Repo{
-		cfg:         c,
-		log:         l,
-		api:         api,
-		ExtType:     t,
-		Revision:    rev,
-		List:        make(map[string]*Extension),
-		UpdateQueue: updateQueue,
+	// Setup Storage
+	err = db.CreateBucket(rc.Name)
+	if err != nil {
+		l.Fatalf("Repo (%s) could not create bucket: %s\n", rc.Name, err)
+	}
+	err = os.MkdirAll(filepath.Join(c.WD, "data", "index", rc.Name), 0766)
+	if err != nil {
+		l.Fatalf("Repo (%s) could not create index dir: %s\n", rc.Name, err)
 	}
 
+	repo := newRepo(c, l, rc, src, rev)
+
 	// Setup Task
-	tasks.Add("13 2 * * * *", repo.jobCheckChangelog)
-	tasks.Add("0 2 31 * * *", repo.jobUpdateMeta)
+	tasks.Add("13 2 * * * *", repo.runJob(JobCheckChangelog))
+	tasks.Add("0 2 31 * * *", repo.runJob(JobUpdateMeta))
 
 	// Load Existing Data
-	err := repo.load()
+	err = repo.load()
 	if err != nil {
-		l.Printf("Repo (%s) could not load data: %s\n", t, err)
+		l.Printf("Repo (%s) could not load data: %s\n", rc.Name, err)
 	}
 
 	repo.save()
@@ -83,6 +102,33 @@ func New(c *config.Config, l *log.Logger, t string, rev int) *Repo {
 	return repo
 }
 
+// NewWithSource returns a Repo reading from src, which is neither stored
+// nor scheduled, for running without a DB
+func NewWithSource(c *config.Config, l *log.Logger, rc config.Repository, src source.Source, rev int) *Repo {
+	return newRepo(c, l, rc, src, rev)
+}
+
+func newRepo(c *config.Config, l *log.Logger, rc config.Repository, src source.Source, rev int) *Repo {
+	return &Repo{
+		cfg:      c,
+		log:      l,
+		src:      src,
+		Name:     rc.Name,
+		ExtType:  rc.Type,
+		Revision: rev,
+		List:     make(map[string]*Extension),
+		running:  make(map[string]bool),
+	}
+}
+
+// SetUpdateHook sets the function called after an Extension is updated
+func (r *Repo) SetUpdateHook(h UpdateHook) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.onUpdate = h
+}
+
 // Len returns the number of extensions in the Repository
 func (r *Repo) Len() uint64 {
 	r.RLock()
@@ -117,12 +163,12 @@ func (r *Repo) save() error {
 
 	rev := strconv.Itoa(r.Revision)
 
-	return db.PutToBucket(r.ExtType, []byte(rev), "repos")
+	return db.PutToBucket(r.Name, []byte(rev), "repos")
 }
 
 // load gets the Repo data from DB
 func (r *Repo) load() error {
-	b, err := db.GetFromBucket(r.ExtType, "repos")
+	b, err := db.GetFromBucket(r.Name, "repos")
 	if err != nil {
 		return err
 	}
@@ -211,11 +257,7 @@ func (r *Repo) UpdateIndex(idx *index.Index) error {
 	}
 
 	// Swap the old index for the new
-	err := r.List[slug].SwapIndexes(idx)
-	if err != nil {
-		r.SetStatus(r.Get(slug), Closed)
-		return err
-	}
+	r.Get(slug).SwapIndexes(idx, r.cfg.IndexVersions)
 
 	r.SetStatus(r.Get(slug), Open)
 
@@ -228,12 +270,25 @@ func (r *Repo) QueueUpdate(slug string, rev string) {
 	if err != nil {
 		r.log.Printf("Revision not a valid int: %s\n", err)
 	}
-	ur := UpdateRequest{
-		Slug:     slug,
-		Repo:     r.ExtType,
-		Revision: revision,
+	err = updateQueue().Add(r.Name, slug, revision)
+	if err != nil {
+		r.log.Printf("Failed queueing update of %s: %s\n", slug, err)
+	}
+}
+
+// QueueUpdates adds requests to the Update Queue, given as a map of slugs
+// to revisions, saving them together
+func (r *Repo) QueueUpdates(list map[string]int) error {
+	if len(list) == 0 {
+		return nil
 	}
-	r.UpdateQueue <- ur
+	return updateQueue().AddAll(r.Name, list)
+}
+
+// PendingUpdates returns the number of Extensions in the Repo waiting to be
+// updated, including failed updates waiting to be retried
+func (r *Repo) PendingUpdates() int {
+	return updateQueue().Pending(r.Name)
 }
 
 // ProcessUpdate performs an update
@@ -244,17 +299,23 @@ func (r *Repo) ProcessUpdate(slug string, rev int) error {
 	}
 	e := r.Get(slug)
 
+	e.RLock()
+	oldVersion := e.Version
+	e.RUnlock()
+
 	// Get latest API info
 	err := r.updateMeta(e)
 	if err != nil {
 		r.SetStatus(e, Closed)
+		r.sendEvent(webhook.ExtensionClosed, e, oldVersion, err)
 		return err
 	}
 
 	// Get latest files
-	err = r.updateFiles(e)
+	err = r.updateFiles(e, oldVersion)
 	if err != nil {
 		r.SetStatus(e, Closed)
+		r.sendEvent(webhook.ExtensionClosed, e, oldVersion, err)
 		return err
 	}
 
@@ -264,9 +325,38 @@ func (r *Repo) ProcessUpdate(slug string, rev int) error {
 	r.SetRev(rev)
 	r.save()
 
+	r.sendEvent(webhook.ExtensionUpdated, e, oldVersion, nil)
+
 	return nil
 }
 
+// extensionEvent is the webhook payload sent when an Extension is updated
+// or closed
+type extensionEvent struct {
+	Repo       string `json:"repo"`
+	Slug       string `json:"slug"`
+	OldVersion string `json:"old_version,omitempty"`
+	Version    string `json:"version,omitempty"`
+	Err        string `json:"error,omitempty"`
+}
+
+// sendEvent notifies webhooks of a change to an Extension
+func (r *Repo) sendEvent(event string, e *Extension, oldVersion string, err error) {
+	e.RLock()
+	data := &extensionEvent{
+		Repo:       r.Name,
+		Slug:       e.Slug,
+		OldVersion: oldVersion,
+		Version:    e.Version,
+	}
+	e.RUnlock()
+	if err != nil {
+		data.Err = err.Error()
+	}
+
+	webhook.Send(event, data)
+}
+
 // updateMeta updates the Info held for the Extension
 func (r *Repo) updateMeta(e *Extension) error {
 	e.RLock()
@@ -274,7 +364,7 @@ func (r *Repo) updateMeta(e *Extension) error {
 	e.RUnlock()
 
 	// Fetch API Response
-	b, err := r.api.GetInfo(r.ExtType, slug)
+	b, err := r.src.Info(slug)
 	if err != nil {
 		return err
 	}
@@ -290,23 +380,41 @@ func (r *Repo) updateMeta(e *Extension) error {
 }
 
 // updateFiles updates the files and index for the Extension
-func (r *Repo) updateFiles(e *Extension) error {
+func (r *Repo) updateFiles(e *Extension, oldVersion string) error {
 	e.RLock()
 	slug := e.Slug
+	version := e.Version
 	e.RUnlock()
 
 	// Download Extension Archive
-	b, err := r.getArchive(slug)
+	// Checkouts also return the version control ref they were built from.
+	var b []byte
+	var vcs *source.Ref
+	var err error
+	if co, ok := r.src.(source.Checkout); ok {
+		b, vcs, err = co.Checkout(slug)
+	} else {
+		b, err = r.src.Archive(slug)
+	}
 	if err != nil {
 		return err
 	}
 
 	// Index extension using Archive bytes
-	ref, files, err := r.generateIndex(b, slug)
+	ref, files, err := r.generateIndex(b, slug, version)
 	if err != nil {
 		return err
 	}
 
+	// Record the ref indexed
+	if vcs != nil {
+		err = ref.SetRef(vcs.Name, vcs.Commit)
+		if err != nil {
+			ref.Remove()
+			return err
+		}
+	}
+
 	// Update File Stats
 	e.Lock()
 	e.Stats = files
@@ -318,68 +426,40 @@ func (r *Repo) updateFiles(e *Extension) error {
 		return err
 	}
 
-	// Update Index
-	err = e.SwapIndexes(idx)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// getArchive fetches the latest archive containing Extension files
-func (r *Repo) getArchive(slug string) ([]byte, error) {
-	var content []byte
-	var err error
-
-	client := client.GetZip()
-	repo := r.ExtType[:len(r.ExtType)-1]
-	URL := fmt.Sprintf(archiveURL, repo, slug)
-
-	req, err := http.NewRequest("GET", URL, nil)
-	if err != nil {
-		r.log.Println(err)
-		return content, err
-	}
-
-	// Set User-Agent
-	agent := r.cfg.Name + "/" + r.cfg.Version
-	req.Header.Set("User-Agent", agent)
+	// Update Index, keeping previous versions
+	e.SwapIndexes(idx, r.cfg.IndexVersions)
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return content, err
+	// Only now the commit is indexed may the Source stop reporting it
+	if tr, ok := r.src.(source.Tracker); ok && vcs != nil {
+		tr.Seen(slug, vcs.Commit)
 	}
-	defer utils.CheckClose(resp.Body, &err)
-
-	if resp.StatusCode != 200 {
-		// Code 404 is acceptable, it means the plugin/theme is no longer available.
-		if resp.StatusCode == 404 {
-			return content, nil
-		}
 
-		log.Printf("Downloading the extension '%s' failed. Response code: %d\n", slug, resp.StatusCode)
-
-		return content, err
+	// Let Watches check the new files
+	r.RLock()
+	hook := r.onUpdate
+	r.RUnlock()
+	if hook != nil {
+		hook(r.Name, e, oldVersion)
 	}
 
-	content, err = ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return content, err
-	}
+	return nil
+}
 
-	return content, nil
+// DownloadArchive fetches the latest stable archive of an Extension
+// An empty archive is returned if it is no longer available.
+func (r *Repo) DownloadArchive(slug string) ([]byte, error) {
+	return r.src.Archive(slug)
 }
 
 // generateIndex indexes the contents of an archive provided in bytes
-func (r *Repo) generateIndex(archive []byte, slug string) (*index.IndexRef, *filestats.Stats, error) {
+func (r *Repo) generateIndex(archive []byte, slug, version string) (*index.IndexRef, *filestats.Stats, error) {
 	id := ulid.New()
-	dst := filepath.Join(r.cfg.WD, "data", "index", r.ExtType, id)
+	dst := filepath.Join(r.cfg.WD, "data", "index", r.Name, id)
 	opts := &index.IndexOptions{
 		ExcludeDotFiles: true,
 	}
 
-	ref, stats, err := index.BuildFromZip(opts, archive, dst, slug)
+	ref, stats, err := index.BuildFromZip(opts, archive, dst, slug, version)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -397,27 +477,64 @@ func (r *Repo) saveExt(e *Extension) error {
 		return err
 	}
 
-	db.PutToBucket(e.Slug, b, r.ExtType)
+	db.PutToBucket(e.Slug, b, r.Name)
 
 	return nil
 }
 
+// Reindex queues an update of an Extension at the current revision, even if
+// it is already queued or has failed on every attempt
+func (r *Repo) Reindex(slug string) error {
+	if !r.Exists(slug) {
+		return fmt.Errorf("Extension %s not found", slug)
+	}
+	return updateQueue().Force(r.Name, map[string]int{slug: r.GetRev()})
+}
+
+// RequeueClosed queues updates of every Closed Extension at the current
+// revision, returning how many were queued
+func (r *Repo) RequeueClosed() (int, error) {
+	rev := r.GetRev()
+	list := make(map[string]int)
+
+	// SetStatus locks the Extension before the Repo, so the Extensions are
+	// checked once the Repo is unlocked
+	r.RLock()
+	exts := make([]*Extension, 0, len(r.List))
+	for _, e := range r.List {
+		exts = append(exts, e)
+	}
+	r.RUnlock()
+
+	for _, e := range exts {
+		e.RLock()
+		if e.Status == Closed {
+			list[e.Slug] = rev
+		}
+		e.RUnlock()
+	}
+
+	if len(list) == 0 {
+		return 0, nil
+	}
+	return len(list), updateQueue().Force(r.Name, list)
+}
+
 // UpdateList updates our Plugin list.
 func (r *Repo) UpdateList(fresh *bool) error {
-	// Fetch list from WPOrg API
-	list, err := r.api.GetList(r.ExtType)
+	// Fetch list from the Source
+	list, err := r.src.List()
 	if err != nil {
 		return err
 	}
-	r.log.Printf("Found %d %s\n", len(list), r.ExtType)
+	r.log.Printf("Found %d %s\n", len(list), r.Name)
 
 	// Get latest Revision
-	revision, err := r.api.GetRevision(r.ExtType)
+	revision, err := r.src.Revision()
 	if err != nil {
 		return err
 	}
-	rev := strconv.Itoa(revision)
-
+	queue := make(map[string]int)
 	for _, ext := range list {
 		if !utf8.Valid([]byte(ext)) {
 			r.log.Printf("Extension slug is not valid UTF8: %s\n", ext)
@@ -428,60 +545,121 @@ func (r *Repo) UpdateList(fresh *bool) error {
 		}
 		// If fresh start we should update all Extensions
 		if *fresh || r.Revision == 0 {
-			r.QueueUpdate(ext, rev)
+			queue[ext] = revision
 		}
 	}
 
+	return r.QueueUpdates(queue)
+}
+
+// StartJob runs a job in the background, a job already running, whether
+// started here or on its schedule, is not run twice
+func (r *Repo) StartJob(name string) error {
+	job, ok := r.jobs()[name]
+	if !ok {
+		return ErrUnknownJob
+	}
+	if !r.claimJob(name) {
+		return ErrJobRunning
+	}
+
+	go func() {
+		defer r.releaseJob(name)
+		job()
+	}()
+
 	return nil
 }
 
-// jobCheckChangelog checks the SVN changelog for updates
-func (r *Repo) jobCheckChangelog() {
-	// Skip if the update queue is not empty
-	if len(r.UpdateQueue) > 0 {
-		return
+// runJob returns the scheduled run of a job, which is skipped while the job
+// is already running
+func (r *Repo) runJob(name string) func() {
+	return func() {
+		if !r.claimJob(name) {
+			r.log.Printf("Skipping %s job for %s, it is already running\n", name, r.Name)
+			return
+		}
+		defer r.releaseJob(name)
+		r.jobs()[name]()
+	}
+}
+
+func (r *Repo) jobs() map[string]func() {
+	return map[string]func(){
+		JobCheckChangelog: r.jobCheckChangelog,
+		JobUpdateMeta:     r.jobUpdateMeta,
+	}
+}
+
+// claimJob marks a job as running, unless it already is
+func (r *Repo) claimJob(name string) bool {
+	r.jobMu.Lock()
+	defer r.jobMu.Unlock()
+
+	if r.running[name] {
+		return false
 	}
+	r.running[name] = true
+	return true
+}
+
+func (r *Repo) releaseJob(name string) {
+	r.jobMu.Lock()
+	defer r.jobMu.Unlock()
+
+	delete(r.running, name)
+}
 
-	latest, err := r.api.GetRevision(r.ExtType)
+// jobCheckChangelog checks the Source changelog for updates
+// Updates already queued are not queued twice, so the check runs while the
+// queue is busy. Once the changes are queued the Repo moves on to the latest
+// revision, as the queue keeps them until they are processed.
+func (r *Repo) jobCheckChangelog() {
+	latest, err := r.src.Revision()
 	if err != nil {
-		r.log.Printf("Failed getting %s Repo revision: %s\n", r.ExtType, err)
+		r.log.Printf("Failed getting %s Repo revision: %s\n", r.Name, err)
+		return
 	}
 	r.RLock()
-	log, err := r.api.GetChangeLog(r.ExtType, r.Revision, latest)
+	list, err := r.src.Changes(r.Revision, latest)
 	if err != nil {
-		r.log.Printf("Failed getting %s Changelog: %s\n", r.ExtType, err)
+		r.log.Printf("Failed getting %s Changelog: %s\n", r.Name, err)
 		r.RUnlock()
 		return
 	}
 
 	// If no changes skip
-	if len(log) == 0 {
-		r.log.Printf("No new %s updates since: %d\n", r.ExtType, r.Revision)
+	if len(list) == 0 {
+		r.log.Printf("No new %s updates since: %d\n", r.Name, r.Revision)
 		r.RUnlock()
 		return
 	}
 	r.RUnlock()
 
-	// Remove Duplicates
-	// Save most recent revision
-	list := make(map[string]string)
-	for _, update := range log {
-		list[update[0]] = update[1]
+	// Queue Updates
+	err = r.QueueUpdates(list)
+	if err != nil {
+		r.log.Printf("Failed queueing %s updates: %s\n", r.Name, err)
+		return
 	}
 
-	// Queue Updates
-	for k, v := range list {
-		r.QueueUpdate(k, v)
+	// Sources may find changes newer than the revision they reported
+	for _, rev := range list {
+		if rev > latest {
+			latest = rev
+		}
 	}
+	r.SetRev(latest)
+	r.save()
 
-	r.log.Printf("%d %s added to the update queue\n", len(list), r.ExtType)
+	r.log.Printf("%d %s added to the update queue\n", len(list), r.Name)
 }
 
-// jobUpdateMeta uses the API to update extension meta data
+// jobUpdateMeta uses the Source to update extension meta data
 func (r *Repo) jobUpdateMeta() {
-	exts, err := r.api.GetList(r.ExtType)
+	exts, err := r.src.List()
 	if err != nil {
-		r.log.Printf("Failed getting %s list: %s\n", r.ExtType, err)
+		r.log.Printf("Failed getting %s list: %s\n", r.Name, err)
 	}
 
 	for _, ext := range exts {
@@ -514,12 +692,12 @@ func (r *Repo) LoadExisting() {
 
 // loadDBData loads all existing Plugin data from the DB
 func (r *Repo) loadDBData() {
-	exts, err := db.GetAllFromBucket(r.ExtType)
+	exts, err := db.GetAllFromBucket(r.Name)
 	if err != nil {
 		return
 	}
 
-	r.log.Printf("Found %d %s in DB\n", len(exts), r.ExtType)
+	r.log.Printf("Found %d %s in DB\n", len(exts), r.Name)
 
 	for slug, b := range exts {
 		var e Extension
@@ -536,15 +714,15 @@ func (r *Repo) loadDBData() {
 
 // loadIndexes reads all existing Indexes and attempts to match them to a Plugin.
 func (r *Repo) loadIndexes() {
-	indexDir := filepath.Join(r.cfg.WD, "data", "index", r.ExtType)
+	indexDir := filepath.Join(r.cfg.WD, "data", "index", r.Name)
 
 	dirs, err := ioutil.ReadDir(indexDir)
 	if err != nil {
-		r.log.Printf("Failed to read %s index dir: %s\n", r.ExtType, err)
+		r.log.Printf("Failed to read %s index dir: %s\n", r.Name, err)
 		return
 	}
 
-	r.log.Printf("Found %d existing %s indexes\n", len(dirs), r.ExtType)
+	r.log.Printf("Found %d existing %s indexes\n", len(dirs), r.Name)
 
 	var loaded int
 
@@ -576,6 +754,11 @@ func (r *Repo) loadIndexes() {
 			continue
 		}
 
+		// Commits already indexed are not changes after a restart
+		if tr, ok := r.src.(source.Tracker); ok && ref.Commit != "" {
+			tr.Seen(ref.Slug, ref.Commit)
+		}
+
 		r.SetStatus(r.Get(ref.Slug), Open)
 
 		loaded++
@@ -586,7 +769,7 @@ func (r *Repo) loadIndexes() {
 // GetInstallsChart ...
 func (r *Repo) GetInstallsChart() string {
 	yr, m, _ := time.Now().Date()
-	key := fmt.Sprintf("installs_%s_%d_%d", r.ExtType, yr, m)
+	key := fmt.Sprintf("installs_%s_%d_%d", r.Name, yr, m)
 
 	b, err := db.GetFromBucket(key, "charts")
 	if err != nil {
@@ -615,7 +798,7 @@ func (r *Repo) GenerateInstallsChart() []byte {
 
 	graph := chart.Chart{
 		XAxis: chart.XAxis{
-			Name:      r.ExtType,
+			Name:      r.Name,
 			NameStyle: chart.StyleShow(),
 			Style:     chart.Style{
 				Show: false,
@@ -655,10 +838,10 @@ func (r *Repo) GenerateInstallsChart() []byte {
 	str = strings.Replace(str, "height=\"400\"", "viewBox=\"0 0 1024 400\"", 1)
 
 	yr, m, _ := time.Now().Date()
-	key := fmt.Sprintf("installs_%s_%d_%d", r.ExtType, yr, m)
+	key := fmt.Sprintf("installs_%s_%d_%d", r.Name, yr, m)
 	err := db.PutToBucket(key, []byte(str), "charts")
 	if err != nil {
-		r.log.Printf("Error saving %s installs Chart: %s\n", r.ExtType, err)
+		r.log.Printf("Error saving %s installs Chart: %s\n", r.Name, err)
 	}
 
 	return []byte(str)
@@ -667,7 +850,7 @@ func (r *Repo) GenerateInstallsChart() []byte {
 // GetSizeChart ...
 func (r *Repo) GetSizeChart() string {
 	yr, m, _ := time.Now().Date()
-	key := fmt.Sprintf("size_%s_%d_%d", r.ExtType, yr, m)
+	key := fmt.Sprintf("size_%s_%d_%d", r.Name, yr, m)
 
 	b, err := db.GetFromBucket(key, "charts")
 	if err != nil {
@@ -703,7 +886,7 @@ func (r *Repo) GenerateSizeChart() []byte {
 
 	graph := chart.Chart{
 		XAxis: chart.XAxis{
-			Name:      r.ExtType,
+			Name:      r.Name,
 			NameStyle: chart.StyleShow(),
 			Style:     chart.Style{
 				Show: false,
@@ -783,10 +966,10 @@ func (r *Repo) GenerateSizeChart() []byte {
 	str = strings.Replace(str, "height=\"400\"", "viewBox=\"0 0 1024 400\"", 1)
 
 	yr, m, _ := time.Now().Date()
-	key := fmt.Sprintf("size_%s_%d_%d", r.ExtType, yr, m)
+	key := fmt.Sprintf("size_%s_%d_%d", r.Name, yr, m)
 	err := db.PutToBucket(key, []byte(str), "charts")
 	if err != nil {
-		r.log.Printf("Error saving %s size Chart: %s\n", r.ExtType, err)
+		r.log.Printf("Error saving %s size Chart: %s\n", r.Name, err)
 	}
 
 	return []byte(str)
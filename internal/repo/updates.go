@@ -2,43 +2,87 @@ package repo
 
 import (
 	"errors"
+	"log"
+	"sync"
+
+	"github.com/wpdirectory/wpdir/internal/repo/queue"
 )
 
-var updateQueue chan UpdateRequest
+var (
+	updates     *queue.Queue
+	updatesOnce sync.Once
+	updateStore = queue.NewDBStore
+)
 
-// UpdateRequest holds info about an extension which needs to be updated
-type UpdateRequest struct {
-	Slug     string
-	Repo     string
-	Revision int
+// updateQueue returns the queue of Extensions waiting to be updated
+// It is loaded from the DB when first used, resuming the updates pending
+// before a restart.
+func updateQueue() *queue.Queue {
+	updatesOnce.Do(func() {
+		q, err := queue.New(updateStore())
+		if err != nil {
+			log.Fatalf("Could not load the update queue: %s\n", err)
+		}
+		updates = q
+	})
+	return updates
 }
 
-func init() {
-	if updateQueue == nil {
-		updateQueue = make(chan UpdateRequest, 200000)
-	}
+// UseUpdateStore keeps the update queue in store instead of the DB
+// It has no effect once the queue has been used.
+func UseUpdateStore(store queue.Store) {
+	updateStore = func() queue.Store { return store }
 }
 
-// StartUpdateWorkers starts Goroutines to process Plugin and Theme updates
-func StartUpdateWorkers(num int, pr *Repo, tr *Repo) {
-	for i := 0; i < num; i++ {
-		go func(queue chan UpdateRequest, pr *Repo, tr *Repo) {
-			for {
-				ur := <-queue
-				var err error
-				switch ur.Repo {
-				case "plugins":
-					err = pr.ProcessUpdate(ur.Slug, ur.Revision)
-				case "themes":
-					err = tr.ProcessUpdate(ur.Slug, ur.Revision)
-				default:
-					err = errors.New("Update failed, Repo not recognized")
-				}
-				if err != nil {
-					// Use the logger embedded into the Plugins Repo
-					pr.log.Printf("Update failed for %s (%s): %s", ur.Slug, ur.Repo, err)
-				}
-			}
-		}(updateQueue, pr, tr)
+// StartUpdateWorkers starts Goroutines to process updates for the Repos
+// Failed updates are retried with backoff until they are dead lettered.
+func StartUpdateWorkers(num int, repos []*Repo) {
+	byName := make(map[string]*Repo, len(repos))
+	for _, r := range repos {
+		byName[r.Name] = r
 	}
+
+	updateQueue().Start(num, func(it *queue.Item) error {
+		r, ok := byName[it.Repo]
+		if !ok {
+			log.Printf("Update failed for %s (%s): Repo not recognized", it.Slug, it.Repo)
+			return errors.New("Repo not recognized")
+		}
+		err := r.ProcessUpdate(it.Slug, it.Revision)
+		if err != nil {
+			r.log.Printf("Update failed for %s (%s) on attempt %d: %s", it.Slug, it.Repo, it.Attempts+1, err)
+		}
+		return err
+	})
+}
+
+// PendingUpdates returns the total number of Extensions waiting to be
+// updated, including failed updates waiting to be retried
+func PendingUpdates() int {
+	return updateQueue().Len()
+}
+
+// DeadUpdates returns the updates which failed on every attempt
+func DeadUpdates() ([]*queue.Item, error) {
+	return updateQueue().Dead()
+}
+
+// UpdateItems returns the Pending updates with their state
+func UpdateItems() []*queue.Status {
+	return updateQueue().Items()
+}
+
+// PauseUpdates stops the workers starting new updates until resumed
+func PauseUpdates() {
+	updateQueue().Pause()
+}
+
+// ResumeUpdates lets paused workers start updates again
+func ResumeUpdates() {
+	updateQueue().Resume()
+}
+
+// UpdatesPaused reports whether the workers are paused
+func UpdatesPaused() bool {
+	return updateQueue().Paused()
 }
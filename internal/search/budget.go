@@ -0,0 +1,84 @@
+package search
+
+import (
+	"sync"
+)
+
+// budget shares a fixed number of Extension search slots between the
+// Searches currently running, so that one Search can not starve the others.
+// Each running Search may hold at most an equal share of the slots.
+type budget struct {
+	total  int
+	inUse  int
+	shares map[string]int
+	cond   *sync.Cond
+	sync.Mutex
+}
+
+// newBudget returns a budget with total slots
+func newBudget(total int) *budget {
+	if total < 1 {
+		total = 1
+	}
+	b := &budget{
+		total:  total,
+		shares: make(map[string]int),
+	}
+	b.cond = sync.NewCond(&b.Mutex)
+	return b
+}
+
+// join registers a running Search
+func (b *budget) join(ID string) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.shares[ID] = 0
+	b.cond.Broadcast()
+}
+
+// leave removes a finished Search, freeing its share for the others
+func (b *budget) leave(ID string) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.inUse -= b.shares[ID]
+	delete(b.shares, ID)
+	b.cond.Broadcast()
+}
+
+// share returns the number of slots each running Search may use
+func (b *budget) share() int {
+	if len(b.shares) == 0 {
+		return b.total
+	}
+	n := b.total / len(b.shares)
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// acquire blocks until the Search may use another slot
+func (b *budget) acquire(ID string) {
+	b.Lock()
+	defer b.Unlock()
+
+	for b.inUse >= b.total || b.shares[ID] >= b.share() {
+		b.cond.Wait()
+	}
+	b.inUse++
+	b.shares[ID]++
+}
+
+// release returns a slot used by the Search
+func (b *budget) release(ID string) {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.shares[ID] > 0 {
+		b.shares[ID]--
+		b.inUse--
+	}
+	b.cond.Broadcast()
+}
@@ -0,0 +1,56 @@
+package search
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBudgetShare(t *testing.T) {
+	b := newBudget(6)
+
+	b.join("first")
+	for i := 0; i < 6; i++ {
+		b.acquire("first")
+	}
+	if b.inUse != 6 {
+		t.Errorf("Expected a single search to use all 6 slots got %d", b.inUse)
+	}
+
+	b.join("second")
+	acquired := make(chan struct{})
+	go func() {
+		b.acquire("second")
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatalf("Expected second search to wait for a free slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The first search is over its share, so a released slot
+	// must go to the second search
+	b.release("first")
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected second search to acquire a slot")
+	}
+
+	for i := 0; i < 2; i++ {
+		b.release("first")
+	}
+	for i := 0; i < 2; i++ {
+		b.acquire("second")
+	}
+
+	if b.shares["first"] != 3 || b.shares["second"] != 3 {
+		t.Errorf("Expected equal shares got %d and %d", b.shares["first"], b.shares["second"])
+	}
+
+	b.leave("first")
+	if b.inUse != 3 {
+		t.Errorf("Expected 3 slots in use got %d", b.inUse)
+	}
+}
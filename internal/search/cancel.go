@@ -0,0 +1,148 @@
+package search
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/wpdirectory/wpdir/internal/db"
+	"github.com/wpdirectory/wpdir/internal/webhook"
+)
+
+var (
+	// ErrSearchNotFound is returned when no Search matches the ID
+	ErrSearchNotFound = errors.New("No search found")
+	// ErrSearchFinished is returned when cancelling a finished Search
+	ErrSearchFinished = errors.New("Search has already finished")
+)
+
+// job allows a queued or running Search to be cancelled
+type job struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// newJob returns a new cancellable job
+func newJob() *job {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &job{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// NewToken returns a random token which identifies the creator of a Search
+func NewToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// hashToken returns the hash stored in place of a creator token
+func hashToken(token string) string {
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsCreator checks the token matches the one used to create a Search
+// Only Searches still in memory can be checked.
+func (sm *Manager) IsCreator(ID, token string) bool {
+	sm.RLock()
+	defer sm.RUnlock()
+	srch, ok := sm.List[ID]
+	if !ok || srch.TokenHash == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(srch.TokenHash), []byte(hashToken(token))) == 1
+}
+
+// Cancel stops a queued or running Search
+// Queued Searches are removed from the queue, running Searches are stopped
+// and the results found so far are saved.
+func (sm *Manager) Cancel(ID string) error {
+	sm.RLock()
+	srch, ok := sm.List[ID]
+	j := sm.jobs[ID]
+	sm.RUnlock()
+	if !ok {
+		if _, err := db.GetSearch(ID); err == nil {
+			return ErrSearchFinished
+		}
+		return ErrSearchNotFound
+	}
+
+	if sm.Queue.Remove(ID) {
+		return sm.saveCancelled(srch)
+	}
+
+	if j != nil {
+		j.cancel()
+	}
+
+	return nil
+}
+
+// saveCancelled stores a Search which was cancelled before it started
+func (sm *Manager) saveCancelled(srch *Search) error {
+	now := time.Now().Format(time.RFC3339)
+
+	sm.Lock()
+	srch.Started = now
+	srch.Completed = now
+	srch.Status = Cancelled
+	s := *srch
+	bytes, err := srch.Marshal()
+	sm.Unlock()
+	if err != nil {
+		return errors.New("Failed Marshalling Search")
+	}
+
+	summary := &Summary{
+		List: make(map[string]*Result),
+	}
+	sbytes, err := summary.Marshal()
+	if err != nil {
+		return errors.New("Failed Marshalling Summary")
+	}
+	err = db.SaveSummary(s.ID, sbytes)
+	if err != nil {
+		return errors.New("Failed Saving Summary to DB")
+	}
+
+	err = db.SaveSearch(s.ID, s.Started, s.Private, bytes)
+	if err != nil {
+		return errors.New("Failed Saving Search to DB")
+	}
+
+	sm.closeStream(s.ID, &Event{
+		Type:   EventCompleted,
+		Status: Cancelled,
+	})
+
+	sm.remove(s.ID)
+
+	webhook.Send(webhook.SearchCompleted, &s)
+
+	return nil
+}
+
+// remove deletes a finished Search from memory
+func (sm *Manager) remove(ID string) {
+	sm.Lock()
+	defer sm.Unlock()
+
+	if j, ok := sm.jobs[ID]; ok {
+		j.cancel()
+		delete(sm.jobs, ID)
+	}
+	delete(sm.List, ID)
+}
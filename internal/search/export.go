@@ -0,0 +1,295 @@
+package search
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/wpdirectory/wpdir/internal/db"
+)
+
+const (
+	// ExportCSV writes one comma separated row per Match
+	ExportCSV = "csv"
+	// ExportJSONL writes one JSON object per line for each Match
+	ExportJSONL = "jsonl"
+	// ExportSARIF writes a SARIF 2.1.0 log for code scanning tools
+	ExportSARIF = "sarif"
+)
+
+// exportFormats maps the export formats to their content types
+var exportFormats = map[string]string{
+	ExportCSV:   "text/csv; charset=utf-8",
+	ExportJSONL: "application/x-ndjson",
+	ExportSARIF: "application/sarif+json",
+}
+
+// ExportContentType returns the content type of an export format and
+// whether the format is supported
+func ExportContentType(format string) (string, bool) {
+	ct, ok := exportFormats[format]
+	return ct, ok
+}
+
+// ExportRow is a single Match along with the Extension it was found in
+type ExportRow struct {
+	Repo           string `json:"repo"`
+	Slug           string `json:"slug"`
+	Version        string `json:"version"`
+	File           string `json:"file"`
+	Line           uint32 `json:"line"`
+	Text           string `json:"text"`
+	ActiveInstalls uint32 `json:"active_installs"`
+}
+
+// exporter writes ExportRows in a particular format
+type exporter interface {
+	begin(srch *Search) error
+	row(r *ExportRow) error
+	end() error
+}
+
+// Export writes every Match of a completed Search to w in the given format
+// Matches are read from the DB one Extension at a time.
+func Export(w io.Writer, ID, format string) error {
+	ex, err := newExporter(w, format)
+	if err != nil {
+		return err
+	}
+
+	b, err := db.GetSearch(ID)
+	if err != nil {
+		return err
+	}
+	var srch Search
+	if err = srch.Unmarshal(b); err != nil {
+		return err
+	}
+
+	b, err = db.GetSummary(ID)
+	if err != nil {
+		return err
+	}
+	var summary Summary
+	if err = summary.Unmarshal(b); err != nil {
+		return err
+	}
+
+	if err = ex.begin(&srch); err != nil {
+		return err
+	}
+
+	err = db.ForEachMatches(ID, func(key string, data []byte) error {
+		var matches Matches
+		if err := matches.Unmarshal(data); err != nil {
+			return err
+		}
+
+		res := summary.List[key]
+		if res == nil {
+			return errors.New("no result found for " + key)
+		}
+		repoName := res.Repo
+		if repoName == "" {
+			repoName = srch.Repo
+		}
+
+		for _, m := range matches.List {
+			version := res.Version
+			if m.Version != "" {
+				version = m.Version
+			}
+			err := ex.row(&ExportRow{
+				Repo:           repoName,
+				Slug:           res.Slug,
+				Version:        version,
+				File:           m.File,
+				Line:           m.LineNum,
+				Text:           m.LineText,
+				ActiveInstalls: res.ActiveInstalls,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return ex.end()
+}
+
+// newExporter returns an exporter writing the format to w
+func newExporter(w io.Writer, format string) (exporter, error) {
+	switch format {
+	case ExportCSV:
+		return &csvExporter{w: csv.NewWriter(w)}, nil
+	case ExportJSONL:
+		return &jsonlExporter{enc: json.NewEncoder(w)}, nil
+	case ExportSARIF:
+		return &sarifExporter{w: w}, nil
+	}
+	return nil, fmt.Errorf("unknown export format: %s", format)
+}
+
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func (e *csvExporter) begin(srch *Search) error {
+	return e.w.Write([]string{"repo", "slug", "version", "file", "line", "text", "active_installs"})
+}
+
+func (e *csvExporter) row(r *ExportRow) error {
+	return e.w.Write([]string{
+		r.Repo,
+		r.Slug,
+		r.Version,
+		r.File,
+		strconv.FormatUint(uint64(r.Line), 10),
+		r.Text,
+		strconv.FormatUint(uint64(r.ActiveInstalls), 10),
+	})
+}
+
+func (e *csvExporter) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonlExporter struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlExporter) begin(srch *Search) error {
+	return nil
+}
+
+func (e *jsonlExporter) row(r *ExportRow) error {
+	return e.enc.Encode(r)
+}
+
+func (e *jsonlExporter) end() error {
+	return nil
+}
+
+// sarifExporter streams a SARIF log, writing the results array one
+// result at a time between a fixed header and footer
+type sarifExporter struct {
+	w     io.Writer
+	count int
+}
+
+// sarifRuleID identifies wpdir search matches in SARIF results
+const sarifRuleID = "wpdir-search"
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	// Properties hold the Extension details not covered by SARIF
+	Properties sarifProperties `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine uint32       `json:"startLine"`
+	Snippet   sarifMessage `json:"snippet"`
+}
+
+type sarifProperties struct {
+	Slug           string `json:"slug"`
+	Version        string `json:"version"`
+	ActiveInstalls uint32 `json:"activeInstalls"`
+}
+
+func (e *sarifExporter) begin(srch *Search) error {
+	driver := map[string]interface{}{
+		"name":           "wpdir",
+		"informationUri": "https://wpdirectory.net",
+		"rules": []map[string]interface{}{
+			{
+				"id":               sarifRuleID,
+				"shortDescription": sarifMessage{Text: "Search: " + srch.Input},
+			},
+		},
+	}
+	b, err := json.Marshal(driver)
+	if err != nil {
+		return err
+	}
+	id, err := json.Marshal("wpdir/search/" + srch.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(e.w, `{"$schema":"https://json.schemastore.org/sarif-2.1.0.json","version":"2.1.0","runs":[{"tool":{"driver":%s},"automationDetails":{"id":%s},"results":[`, b, id)
+	return err
+}
+
+func (e *sarifExporter) row(r *ExportRow) error {
+	b, err := json.Marshal(&sarifResult{
+		RuleID:  sarifRuleID,
+		Level:   "note",
+		Message: sarifMessage{Text: fmt.Sprintf("Match in %s %s", r.Slug, r.Version)},
+		Locations: []sarifLocation{
+			{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{
+						URI:       r.Slug + "/" + r.File,
+						URIBaseID: r.Repo,
+					},
+					Region: sarifRegion{
+						StartLine: r.Line,
+						Snippet:   sarifMessage{Text: r.Text},
+					},
+				},
+			},
+		},
+		Properties: sarifProperties{
+			Slug:           r.Slug,
+			Version:        r.Version,
+			ActiveInstalls: r.ActiveInstalls,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if e.count > 0 {
+		if _, err = io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.w.Write(b)
+	return err
+}
+
+func (e *sarifExporter) end() error {
+	_, err := io.WriteString(e.w, "]}]}\n")
+	return err
+}
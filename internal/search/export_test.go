@@ -0,0 +1,81 @@
+package search
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var exportRows = []*ExportRow{
+	{Repo: "plugins", Slug: "akismet", Version: "4.0.8", File: "class.akismet.php", Line: 12, Text: `$a = "x, y";`, ActiveInstalls: 5000000},
+	{Repo: "themes", Slug: "astra", Version: "1.4.8", File: "inc/core.php", Line: 3, Text: "unserialize( $b );", ActiveInstalls: 600000},
+}
+
+func export(t *testing.T, format string) []byte {
+	var buf bytes.Buffer
+	ex, err := newExporter(&buf, format)
+	if err != nil {
+		t.Fatalf("Could not create exporter: %s", err)
+	}
+	if err := ex.begin(&Search{ID: "01CMT", Input: "unserialize\\("}); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range exportRows {
+		if err := ex.row(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ex.end(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExportCSV(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(export(t, ExportCSV))).ReadAll()
+	if err != nil {
+		t.Fatalf("Could not read CSV: %s", err)
+	}
+	if len(records) != len(exportRows)+1 {
+		t.Fatalf("Expected %d records got %d", len(exportRows)+1, len(records))
+	}
+	want := []string{"plugins", "akismet", "4.0.8", "class.akismet.php", "12", `$a = "x, y";`, "5000000"}
+	if strings.Join(records[1], "|") != strings.Join(want, "|") {
+		t.Errorf("Expected %+v got %+v", want, records[1])
+	}
+}
+
+func TestExportJSONL(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(string(export(t, ExportJSONL))), "\n")
+	if len(lines) != len(exportRows) {
+		t.Fatalf("Expected %d lines got %d", len(exportRows), len(lines))
+	}
+	var row ExportRow
+	if err := json.Unmarshal([]byte(lines[1]), &row); err != nil {
+		t.Fatalf("Could not decode line: %s", err)
+	}
+	if row != *exportRows[1] {
+		t.Errorf("Expected %+v got %+v", *exportRows[1], row)
+	}
+}
+
+func TestExportSARIF(t *testing.T) {
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []sarifResult `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(export(t, ExportSARIF), &log); err != nil {
+		t.Fatalf("Could not decode SARIF: %s", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != len(exportRows) {
+		t.Fatalf("Expected one run with %d results got %+v", len(exportRows), log)
+	}
+	loc := log.Runs[0].Results[1].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "astra/inc/core.php" || loc.Region.StartLine != 3 {
+		t.Errorf("Expected astra/inc/core.php:3 got %s:%d", loc.ArtifactLocation.URI, loc.Region.StartLine)
+	}
+}
@@ -0,0 +1,268 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wpdirectory/wpdir/internal/repo"
+)
+
+// dateLayout is the format of the date range in Filters
+const dateLayout = "2006-01-02"
+
+// lastUpdatedLayouts are the formats used by the WordPress.org API for
+// the LastUpdated time of Plugins and Themes
+var lastUpdatedLayouts = []string{
+	"2006-01-02 3:04pm MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// htmlTag matches the markup around Extension authors
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// filter selects the Extensions a Search is run over
+type filter struct {
+	slugs         map[string]bool
+	author        string
+	tags          []string
+	minInstalls   int
+	maxInstalls   int
+	requiresPHP   string
+	testedMin     string
+	testedMax     string
+	updatedAfter  time.Time
+	updatedBefore time.Time
+}
+
+// Validate checks the dates and versions in the Filters can be used
+func (f *Filters) Validate() error {
+	_, err := newFilter(f)
+	return err
+}
+
+// newFilter parses Filters, a nil filter matches every Extension
+func newFilter(f *Filters) (*filter, error) {
+	if f == nil {
+		return nil, nil
+	}
+
+	flt := &filter{
+		author:      strings.ToLower(strings.TrimSpace(f.Author)),
+		minInstalls: int(f.MinInstalls),
+		maxInstalls: int(f.MaxInstalls),
+		requiresPHP: f.RequiresPHP,
+		testedMin:   f.TestedMin,
+		testedMax:   f.TestedMax,
+	}
+
+	if f.MaxInstalls > 0 && f.MinInstalls > f.MaxInstalls {
+		return nil, fmt.Errorf("min_installs is greater than max_installs")
+	}
+
+	for name, v := range map[string]string{
+		"requires_php": f.RequiresPHP,
+		"tested_min":   f.TestedMin,
+		"tested_max":   f.TestedMax,
+	} {
+		if v != "" && !validVersion(v) {
+			return nil, fmt.Errorf("%s is not a valid version: %s", name, v)
+		}
+	}
+
+	var err error
+	if f.UpdatedAfter != "" {
+		if flt.updatedAfter, err = time.Parse(dateLayout, f.UpdatedAfter); err != nil {
+			return nil, fmt.Errorf("updated_after is not a valid date: %s", f.UpdatedAfter)
+		}
+	}
+	if f.UpdatedBefore != "" {
+		if flt.updatedBefore, err = time.Parse(dateLayout, f.UpdatedBefore); err != nil {
+			return nil, fmt.Errorf("updated_before is not a valid date: %s", f.UpdatedBefore)
+		}
+	}
+
+	if len(f.Slugs) > 0 {
+		flt.slugs = make(map[string]bool, len(f.Slugs))
+		for _, slug := range f.Slugs {
+			flt.slugs[slug] = true
+		}
+	}
+
+	for _, tag := range f.Tags {
+		flt.tags = append(flt.tags, strings.ToLower(tag))
+	}
+
+	return flt, nil
+}
+
+// extensions returns the Extensions of a Repository the filter matches
+// Only the Repository list is locked while reading it, Extensions are then
+// checked on their own.
+func (f *filter) extensions(r *repo.Repo) []*repo.Extension {
+	r.RLock()
+	var list []*repo.Extension
+	if f != nil && f.slugs != nil {
+		for slug := range f.slugs {
+			if e, ok := r.List[slug]; ok {
+				list = append(list, e)
+			}
+		}
+	} else {
+		list = make([]*repo.Extension, 0, len(r.List))
+		for _, e := range r.List {
+			list = append(list, e)
+		}
+	}
+	r.RUnlock()
+
+	if f == nil {
+		return list
+	}
+
+	matched := list[:0]
+	for _, e := range list {
+		if f.match(e) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// match reports whether the Extension metadata satisfies the filter
+func (f *filter) match(e *repo.Extension) bool {
+	e.RLock()
+	defer e.RUnlock()
+
+	if f.slugs != nil && !f.slugs[e.Slug] {
+		return false
+	}
+	if f.minInstalls > 0 && e.ActiveInstalls < f.minInstalls {
+		return false
+	}
+	if f.maxInstalls > 0 && e.ActiveInstalls > f.maxInstalls {
+		return false
+	}
+	if f.author != "" && !f.matchAuthor(e) {
+		return false
+	}
+	if len(f.tags) > 0 && !f.matchTags(e) {
+		return false
+	}
+
+	// Extensions without a PHP requirement run on any version
+	if f.requiresPHP != "" && e.RequiresPHP != "" && compareVersions(e.RequiresPHP, f.requiresPHP) > 0 {
+		return false
+	}
+	if f.testedMin != "" || f.testedMax != "" {
+		if e.Tested == "" {
+			return false
+		}
+		if f.testedMin != "" && compareVersions(e.Tested, f.testedMin) < 0 {
+			return false
+		}
+		if f.testedMax != "" && compareVersions(e.Tested, f.testedMax) > 0 {
+			return false
+		}
+	}
+
+	if !f.updatedAfter.IsZero() || !f.updatedBefore.IsZero() {
+		updated, ok := parseLastUpdated(e.LastUpdated)
+		if !ok {
+			return false
+		}
+		if !f.updatedAfter.IsZero() && updated.Before(f.updatedAfter) {
+			return false
+		}
+		// The before date is inclusive of the whole day
+		if !f.updatedBefore.IsZero() && !updated.Before(f.updatedBefore.AddDate(0, 0, 1)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchAuthor compares the author name, without markup, or the username
+// at the end of the author profile URL, ignoring case
+func (f *filter) matchAuthor(e *repo.Extension) bool {
+	name := strings.ToLower(strings.TrimSpace(htmlTag.ReplaceAllString(e.Author, "")))
+	if name == f.author {
+		return true
+	}
+
+	profile := strings.ToLower(strings.TrimRight(e.AuthorProfile, "/"))
+	return profile != "" && strings.HasSuffix(profile, "/"+f.author)
+}
+
+// matchTags reports whether the Extension has any of the tags, compared
+// against both the tag slug and name
+func (f *filter) matchTags(e *repo.Extension) bool {
+	for _, tag := range e.Tags {
+		for _, t := range tag {
+			for _, want := range f.tags {
+				if strings.ToLower(t) == want {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// parseLastUpdated parses the LastUpdated time of an Extension
+func parseLastUpdated(s string) (time.Time, bool) {
+	for _, layout := range lastUpdatedLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// validVersion reports whether v is a dotted version number
+func validVersion(v string) bool {
+	for _, part := range strings.Split(v, ".") {
+		if _, err := strconv.Atoi(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersions compares dotted version numbers, returning -1, 0 or 1
+// Missing parts count as zero and non numeric suffixes such as -beta1
+// are ignored.
+func compareVersions(a, b string) int {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x = versionPart(as[i])
+		}
+		if i < len(bs) {
+			y = versionPart(bs[i])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionPart returns the leading number of a version part
+func versionPart(s string) int {
+	end := 0
+	for end < len(s) && '0' <= s[end] && s[end] <= '9' {
+		end++
+	}
+	n, _ := strconv.Atoi(s[:end])
+	return n
+}
@@ -0,0 +1,110 @@
+package search
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/wpdirectory/wpdir/internal/repo"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"4.9", "4.9.0", 0},
+		{"4.9.8", "4.10", -1},
+		{"5.0-beta1", "4.9.8", 1},
+		{"7.0", "5.6", 1},
+	}
+
+	for _, test := range tests {
+		got := compareVersions(test.a, test.b)
+		if got != test.want {
+			t.Errorf("Expected %d got %d comparing %s and %s", test.want, got, test.a, test.b)
+		}
+	}
+}
+
+func TestFilterExtensions(t *testing.T) {
+	r := &repo.Repo{
+		List: map[string]*repo.Extension{
+			"akismet": {
+				Slug:           "akismet",
+				Author:         `<a href="https://automattic.com">Automattic</a>`,
+				AuthorProfile:  "https://profiles.wordpress.org/automattic",
+				ActiveInstalls: 5000000,
+				RequiresPHP:    "5.2",
+				Tested:         "4.9.8",
+				LastUpdated:    "2018-08-03 2:46pm GMT",
+				Tags:           [][]string{{"spam", "spam"}, {"antispam", "antispam"}},
+			},
+			"hello-dolly": {
+				Slug:           "hello-dolly",
+				Author:         "Matt Mullenweg",
+				AuthorProfile:  "https://profiles.wordpress.org/matt",
+				ActiveInstalls: 600000,
+				Tested:         "4.6.12",
+				LastUpdated:    "2016-01-12 3:00am GMT",
+			},
+			"new-thing": {
+				Slug:           "new-thing",
+				Author:         "Someone",
+				ActiveInstalls: 10,
+				RequiresPHP:    "7.1",
+				Tested:         "4.9.8",
+				LastUpdated:    "2018-08-01",
+			},
+		},
+	}
+
+	tests := []struct {
+		filters *Filters
+		want    []string
+	}{
+		{nil, []string{"akismet", "hello-dolly", "new-thing"}},
+		{&Filters{Slugs: []string{"akismet", "missing"}}, []string{"akismet"}},
+		{&Filters{Author: "automattic"}, []string{"akismet"}},
+		{&Filters{Author: "matt"}, []string{"hello-dolly"}},
+		{&Filters{Tags: []string{"Spam"}}, []string{"akismet"}},
+		{&Filters{MinInstalls: 10000}, []string{"akismet", "hello-dolly"}},
+		{&Filters{MinInstalls: 10000, MaxInstalls: 1000000}, []string{"hello-dolly"}},
+		{&Filters{RequiresPHP: "5.6"}, []string{"akismet", "hello-dolly"}},
+		{&Filters{TestedMin: "4.9"}, []string{"akismet", "new-thing"}},
+		{&Filters{TestedMax: "4.7"}, []string{"hello-dolly"}},
+		{&Filters{UpdatedAfter: "2018-01-01", UpdatedBefore: "2018-08-01"}, []string{"new-thing"}},
+	}
+
+	for _, test := range tests {
+		flt, err := newFilter(test.filters)
+		if err != nil {
+			t.Fatalf("Could not create filter: %s", err)
+		}
+		var got []string
+		for _, e := range flt.extensions(r) {
+			got = append(got, e.Slug)
+		}
+		sort.Strings(got)
+		if len(got) != len(test.want) {
+			t.Errorf("Expected %+v got %+v for %+v", test.want, got, test.filters)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("Expected %+v got %+v for %+v", test.want, got, test.filters)
+				break
+			}
+		}
+	}
+
+	invalid := []*Filters{
+		{MinInstalls: 10, MaxInstalls: 5},
+		{TestedMin: "latest"},
+		{UpdatedAfter: "01/02/2018"},
+	}
+	for _, f := range invalid {
+		if err := f.Validate(); err == nil {
+			t.Errorf("Expected an error for %+v", f)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package search
+
+import (
+	"unicode/utf8"
+
+	"github.com/wpdirectory/wpdir/internal/index"
+)
+
+// newMatches converts the Matches found in an Extension, keeping the
+// Matches of each file together and counting them per file
+func newMatches(slug string, resp *index.SearchResponse, maxLen int) *Matches {
+	ms := &Matches{}
+	for _, fm := range resp.Matches {
+		ms.Files = append(ms.Files, &FileCount{
+			File:    fm.Filename,
+			Matches: uint32(len(fm.Matches)),
+		})
+		for _, im := range fm.Matches {
+			ms.List = append(ms.List, newMatch(slug, fm.Filename, im, maxLen))
+		}
+	}
+	return ms
+}
+
+// collectMatches combines the Matches found in each version of an
+// Extension searched, returning them with the number of files matched. If
+// tagVersions is set Matches and file counts are marked with their version
+// and the versions with Matches are also returned.
+func collectMatches(slug string, resps []*index.SearchResponse, maxLen int, tagVersions bool) (*Matches, []string, int) {
+	ms := &Matches{}
+	var versions []string
+	var files int
+	for _, resp := range resps {
+		if len(resp.Matches) == 0 {
+			continue
+		}
+		vm := newMatches(slug, resp, maxLen)
+		if tagVersions {
+			for _, m := range vm.List {
+				m.Version = resp.Version
+			}
+			for _, f := range vm.Files {
+				f.Version = resp.Version
+			}
+			versions = append(versions, resp.Version)
+		}
+		ms.List = append(ms.List, vm.List...)
+		ms.Files = append(ms.Files, vm.Files...)
+		files += resp.FilesWithMatch
+	}
+	return ms, versions, files
+}
+
+// newMatch converts an index Match into a stored Match
+// Lines longer than maxLen bytes are cut at rune boundaries, keeping the
+// matched text in view. A maxLen of zero keeps whole lines.
+func newMatch(slug, file string, im *index.Match, maxLen int) *Match {
+	text, offset, truncated := truncateAround(im.Line, im.Start, im.End, maxLen)
+
+	m := &Match{
+		Slug:       slug,
+		File:       file,
+		LineNum:    uint32(im.LineNumber),
+		LineText:   text,
+		MatchStart: uint32(clamp(im.Start-offset, 0, len(text))),
+		MatchEnd:   uint32(clamp(im.End-offset, 0, len(text))),
+		TextOffset: uint32(offset),
+		Truncated:  truncated,
+	}
+	for _, line := range im.Before {
+		m.Before = append(m.Before, truncate(line, maxLen))
+	}
+	for _, line := range im.After {
+		m.After = append(m.After, truncate(line, maxLen))
+	}
+
+	return m
+}
+
+// truncate cuts s to at most maxLen bytes without splitting a rune
+func truncate(s string, maxLen int) string {
+	text, _, _ := truncateAround(s, 0, 0, maxLen)
+	return text
+}
+
+// truncateAround cuts s to at most maxLen bytes without splitting a rune
+// If the span [start, end) would be cut off the text begins shortly before
+// it instead. The byte offset of the returned text within s is returned.
+func truncateAround(s string, start, end, maxLen int) (string, int, bool) {
+	if maxLen <= 0 || len(s) <= maxLen {
+		return s, 0, false
+	}
+
+	from := 0
+	if end > maxLen {
+		// Leave a little of the line before the match for context
+		from = start - maxLen/4
+		if end-from > maxLen {
+			from = start
+		}
+		from = clamp(from, 0, len(s))
+		for from > 0 && !utf8.RuneStart(s[from]) {
+			from--
+		}
+	}
+
+	to := clamp(from+maxLen, from, len(s))
+	for to < len(s) && to > from && !utf8.RuneStart(s[to]) {
+		to--
+	}
+
+	return s[from:to], from, true
+}
+
+func clamp(n, min, max int) int {
+	if n < min {
+		return min
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
@@ -0,0 +1,122 @@
+package search
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/wpdirectory/wpdir/internal/index"
+)
+
+func TestTruncateAround(t *testing.T) {
+	long := strings.Repeat("é", 60) + "unserialize(" + strings.Repeat("x", 50)
+
+	tests := []struct {
+		s          string
+		start, end int
+		max        int
+		want       string
+		offset     int
+	}{
+		{"short", 0, 5, 100, "short", 0},
+		{"short", 0, 5, 0, "short", 0},
+		{"ééé", 0, 0, 3, "é", 0},
+		{long, 120, 132, 40, "ééééé" + "unserialize(" + strings.Repeat("x", 18), 110},
+	}
+
+	for _, test := range tests {
+		got, offset, _ := truncateAround(test.s, test.start, test.end, test.max)
+		if got != test.want || offset != test.offset {
+			t.Errorf("Expected %q at %d got %q at %d", test.want, test.offset, got, offset)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("Expected valid UTF-8 got %q", got)
+		}
+	}
+}
+
+func TestNewMatch(t *testing.T) {
+	line := strings.Repeat("a", 200) + "eval( $x );"
+	m := newMatch("hello-dolly", "hello.php", &index.Match{
+		Line:       line,
+		LineNumber: 7,
+		Before:     []string{strings.Repeat("ü", 100)},
+		After:      []string{"}"},
+		Start:      200,
+		End:        205,
+	}, 100)
+
+	if got := m.LineText[m.MatchStart:m.MatchEnd]; got != "eval(" {
+		t.Errorf("Expected eval( got %q", got)
+	}
+	if !m.Truncated || int(m.TextOffset)+len(m.LineText) > len(line) {
+		t.Errorf("Expected a truncated window of the line got %+v", m)
+	}
+	if len(m.Before[0]) != 100 || !utf8.ValidString(m.Before[0]) {
+		t.Errorf("Expected 100 bytes of valid UTF-8 got %d", len(m.Before[0]))
+	}
+	if m.After[0] != "}" {
+		t.Errorf("Expected } got %q", m.After[0])
+	}
+}
+
+func TestNewMatches(t *testing.T) {
+	resp := &index.SearchResponse{
+		Matches: []*index.FileMatch{
+			{Filename: "a.php", Matches: []*index.Match{{Line: "eval(1)", LineNumber: 3}, {Line: "eval(2)", LineNumber: 9}}},
+			{Filename: "b.php", Matches: []*index.Match{{Line: "eval(3)", LineNumber: 1}}},
+		},
+		FilesWithMatch: 2,
+	}
+
+	ms := newMatches("hello-dolly", resp, 0)
+	if len(ms.List) != 3 {
+		t.Fatalf("Expected 3 matches got %d", len(ms.List))
+	}
+	want := []string{"a.php", "a.php", "b.php"}
+	for i, m := range ms.List {
+		if m.File != want[i] {
+			t.Errorf("Expected %s got %s", want[i], m.File)
+		}
+	}
+	if len(ms.Files) != 2 || ms.Files[0].Matches != 2 || ms.Files[1].Matches != 1 {
+		t.Errorf("Expected per file counts of 2 and 1 got %+v", ms.Files)
+	}
+}
+
+func TestCollectMatches(t *testing.T) {
+	resps := []*index.SearchResponse{
+		{
+			Version:        "1.2",
+			FilesWithMatch: 1,
+			Matches:        []*index.FileMatch{{Filename: "a.php", Matches: []*index.Match{{Line: "eval(1)", LineNumber: 3}}}},
+		},
+		{Version: "1.1"},
+		{
+			Version:        "1.0",
+			FilesWithMatch: 2,
+			Matches: []*index.FileMatch{
+				{Filename: "a.php", Matches: []*index.Match{{Line: "eval(1)", LineNumber: 2}}},
+				{Filename: "b.php", Matches: []*index.Match{{Line: "eval(2)", LineNumber: 7}}},
+			},
+		},
+	}
+
+	ms, versions, files := collectMatches("hello-dolly", resps, 0, true)
+	if len(ms.List) != 3 || files != 3 {
+		t.Fatalf("Expected 3 matches in 3 files got %d in %d", len(ms.List), files)
+	}
+	if want := []string{"1.2", "1.0"}; strings.Join(versions, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %+v got %+v", want, versions)
+	}
+	for i, want := range []string{"1.2", "1.0", "1.0"} {
+		if ms.List[i].Version != want || ms.Files[i].Version != want {
+			t.Errorf("Expected version %s got %s and %s", want, ms.List[i].Version, ms.Files[i].Version)
+		}
+	}
+
+	ms, versions, _ = collectMatches("hello-dolly", resps[:1], 0, false)
+	if versions != nil || ms.List[0].Version != "" {
+		t.Errorf("Expected untagged matches got %+v %+v", versions, ms.List[0])
+	}
+}
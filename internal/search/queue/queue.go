@@ -1,57 +1,98 @@
 package queue
 
 import (
+	"errors"
 	"sync"
 
 	"github.com/wpdirectory/wpdir/internal/metrics"
 )
 
+// ErrQueueFull is returned when adding to a Queue with no room left
+var ErrQueueFull = errors.New("Queue is full")
+
 // Queue contains information about and helper funcs for the queue
+// Positions are tracked using the number of items added and taken, so they
+// stay accurate when several workers take from the queue at once.
 type Queue struct {
-	queue chan string
-	pos   map[string]int
+	queue   chan string
+	pos     map[string]int
+	removed map[string]int
+	added   int
+	taken   int
 	sync.RWMutex
 }
 
 // New returns a Queue struct
 func New(len int) *Queue {
 	return &Queue{
-		queue: make(chan string, len),
-		pos:   make(map[string]int),
+		queue:   make(chan string, len),
+		pos:     make(map[string]int),
+		removed: make(map[string]int),
 	}
 }
 
-// Add pushes an ID onto the Queue
-func (q *Queue) Add(id string) {
-	q.queue <- id
-
+// Add pushes an ID onto the Queue, or returns ErrQueueFull without waiting
+// if there is no room left
+func (q *Queue) Add(id string) error {
+	// Hold the lock while sending so the position is recorded before
+	// a worker can take the ID, the send never blocks
 	q.Lock()
-	q.pos[id] = len(q.queue)
+	select {
+	case q.queue <- id:
+	default:
+		q.Unlock()
+		return ErrQueueFull
+	}
+	q.added++
+	q.pos[id] = q.added
 	q.Unlock()
 
 	metrics.SearchQueue.Inc()
+
+	return nil
 }
 
 // Get returns an item from the queue
 // Blocks until available
 func (q *Queue) Get() string {
-	id := <-q.queue
+	for {
+		id := <-q.queue
+
+		q.Lock()
+		q.taken++
+		// Skip IDs which were removed while queued
+		if _, ok := q.removed[id]; ok {
+			delete(q.removed, id)
+			q.Unlock()
+			continue
+		}
+		delete(q.pos, id)
+		q.Unlock()
 
+		metrics.SearchQueue.Dec()
+
+		return id
+	}
+}
+
+// Remove takes an ID out of the queue
+// returns false if ID not found.
+func (q *Queue) Remove(id string) bool {
 	q.Lock()
 	defer q.Unlock()
+	pos, ok := q.pos[id]
+	if !ok {
+		return false
+	}
 	delete(q.pos, id)
-	q.decrementPos()
+	if q.removed == nil {
+		q.removed = make(map[string]int)
+	}
+	q.removed[id] = pos
 
 	metrics.SearchQueue.Dec()
 
-	return id
-}
-
-// decrementPos decrements each ID by one.
-func (q *Queue) decrementPos() {
-	for id := range q.pos {
-		q.pos[id]--
-	}
+	return true
 }
 
 // Pos returns the position of ID in the queue
@@ -63,5 +104,14 @@ func (q *Queue) Pos(id string) int {
 	if !ok {
 		return -1
 	}
-	return pos
+
+	// Removed IDs ahead in the queue no longer count
+	ahead := 0
+	for _, rpos := range q.removed {
+		if rpos < pos {
+			ahead++
+		}
+	}
+
+	return pos - q.taken - ahead
 }
@@ -1,6 +1,7 @@
 package queue
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/wpdirectory/wpdir/internal/metrics"
@@ -112,3 +113,105 @@ func TestDecrementPos(t *testing.T) {
 		}
 	}
 }
+
+func TestPosConcurrentGet(t *testing.T) {
+	queue := New(10)
+
+	ids := []string{
+		"01CH6CNP575QSN84B4YH1FRGYC",
+		"01CH5K5GW8BQJ1CSTM2B4EZ5SZ",
+		"01CH40V4G0DZ2GRNAQ3Z3FT85H",
+		"01CH40V4G0DZ2GRNAQ3Z3FT85J",
+		"01CH40V4G0DZ2GRNAQ3Z3FT85K",
+	}
+
+	for _, id := range ids {
+		queue.Add(id)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			queue.Get()
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	for key, id := range ids[2:] {
+		got := queue.Pos(id)
+		want := key + 1
+		if got != want {
+			t.Errorf("Expected position %d got %d", want, got)
+		}
+	}
+}
+
+func TestRemove(t *testing.T) {
+	queue := New(10)
+
+	ids := []string{
+		"01CH6CNP575QSN84B4YH1FRGYC",
+		"01CH5K5GW8BQJ1CSTM2B4EZ5SZ",
+		"01CH40V4G0DZ2GRNAQ3Z3FT85H",
+	}
+
+	for _, id := range ids {
+		queue.Add(id)
+	}
+
+	if !queue.Remove(ids[1]) {
+		t.Errorf("Expected %s to be removed", ids[1])
+	}
+	if queue.Remove(ids[1]) {
+		t.Errorf("Expected %s to already be removed", ids[1])
+	}
+
+	got := queue.Pos(ids[1])
+	if got != -1 {
+		t.Errorf("Expected invalid key (%d) got %d", -1, got)
+	}
+	got = queue.Pos(ids[2])
+	if got != 2 {
+		t.Errorf("Expected position %d got %d", 2, got)
+	}
+
+	want := []string{ids[0], ids[2]}
+	for _, id := range want {
+		got := queue.Get()
+		if got != id {
+			t.Errorf("Expected ID %s got %s", id, got)
+		}
+	}
+}
+
+func TestAddFull(t *testing.T) {
+	queue := New(2)
+
+	ids := []string{
+		"01CH6CNP575QSN84B4YH1FRGYC",
+		"01CH5K5GW8BQJ1CSTM2B4EZ5SZ",
+		"01CH40V4G0DZ2GRNAQ3Z3FT85H",
+	}
+
+	for _, id := range ids[:2] {
+		if err := queue.Add(id); err != nil {
+			t.Errorf("Expected %s to be added got %s", id, err)
+		}
+	}
+	if err := queue.Add(ids[2]); err != ErrQueueFull {
+		t.Errorf("Expected %v got %v", ErrQueueFull, err)
+	}
+	if got := queue.Pos(ids[2]); got != -1 {
+		t.Errorf("Expected invalid key (%d) got %d", -1, got)
+	}
+
+	queue.Get()
+	if err := queue.Add(ids[2]); err != nil {
+		t.Errorf("Expected %s to be added got %s", ids[2], err)
+	}
+	if got := queue.Pos(ids[2]); got != 2 {
+		t.Errorf("Expected position %d got %d", 2, got)
+	}
+}
@@ -1,6 +1,8 @@
 package search
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"log"
 	"math"
@@ -15,26 +17,45 @@ import (
 	"github.com/wpdirectory/wpdir/internal/repo"
 	"github.com/wpdirectory/wpdir/internal/search/queue"
 	"github.com/wpdirectory/wpdir/internal/ulid"
+	"github.com/wpdirectory/wpdir/internal/webhook"
 )
 
 // Manager controls the processing and storage of searches
 type Manager struct {
-	Queue   *queue.Queue
-	List    map[string]*Search
-	Plugins *repo.Repo
-	Themes  *repo.Repo
-	limit   int
-	Loaded  bool
+	Queue      *queue.Queue
+	List       map[string]*Search
+	repos      map[string]*repo.Repo
+	repoNames  []string
+	budget     *budget
+	concurrent int
+	timeout    time.Duration
+	lineLength int
+	Loaded     bool
+	streams    map[string]*Stream
+	jobs       map[string]*job
+	watchMu    sync.Mutex
 	sync.RWMutex
 }
 
 // NewManager returns a new SearchManager struct
-func NewManager(limit int) *Manager {
+// limit is the number of Extensions searched at once, shared between up to
+// concurrent Searches. Searches running longer than timeout are stopped.
+// Stored match lines are cut to lineLength bytes, zero keeps whole lines.
+func NewManager(limit, concurrent int, timeout time.Duration, lineLength int) *Manager {
+	if concurrent < 1 {
+		concurrent = 1
+	}
 	return &Manager{
-		Queue:  queue.New(100),
-		List:   make(map[string]*Search),
-		limit:  limit,
-		Loaded: false,
+		Queue:      queue.New(100),
+		List:       make(map[string]*Search),
+		repos:      make(map[string]*repo.Repo),
+		budget:     newBudget(limit),
+		concurrent: concurrent,
+		timeout:    timeout,
+		lineLength: lineLength,
+		Loaded:     false,
+		streams:    make(map[string]*Stream),
+		jobs:       make(map[string]*job),
 	}
 }
 
@@ -71,48 +92,136 @@ func (sm *Manager) Exists(ID string) bool {
 	return ok
 }
 
+// AddRepo registers a Repository under its name
+func (sm *Manager) AddRepo(r *repo.Repo) {
+	sm.Lock()
+	defer sm.Unlock()
+	if _, ok := sm.repos[r.Name]; !ok {
+		sm.repoNames = append(sm.repoNames, r.Name)
+	}
+	sm.repos[r.Name] = r
+}
+
+// Repo returns the Repository with the given name, or nil if it is not known
+func (sm *Manager) Repo(name string) *repo.Repo {
+	sm.RLock()
+	defer sm.RUnlock()
+	return sm.repos[name]
+}
+
+// Repos returns the registered Repositories in the order they were added
+func (sm *Manager) Repos() []*repo.Repo {
+	sm.RLock()
+	defer sm.RUnlock()
+	repos := make([]*repo.Repo, len(sm.repoNames))
+	for i, name := range sm.repoNames {
+		repos[i] = sm.repos[name]
+	}
+	return repos
+}
+
+// RepoNames returns the names of the registered Repositories in the order
+// they were added
+func (sm *Manager) RepoNames() []string {
+	sm.RLock()
+	defer sm.RUnlock()
+	return append([]string(nil), sm.repoNames...)
+}
+
+// RepoNames returns the Repositories searched, Searches saved before
+// several Repositories could be searched at once only have Repo set
+func (m *Search) RepoNames() []string {
+	if len(m.Repos) > 0 {
+		return m.Repos
+	}
+	return []string{m.Repo}
+}
+
+// ResultKey returns the key used to store an Extension's Result and Matches
+// Searches over several Repositories prefix the slug with the Repository
+// name, as plugins and themes may share a slug.
+func (m *Search) ResultKey(repoName, slug string) string {
+	if len(m.Repos) > 1 {
+		return repoName + "/" + slug
+	}
+	return slug
+}
+
 // Empty deletes all Search data from DB
 func (sm *Manager) Empty() error {
 	return db.DeleteSearches()
 }
 
 // NewSearch creates a new Search in memory and adds it to the queue
-func (sm *Manager) NewSearch(sr Request) string {
+// queue.ErrQueueFull is returned, and the Search dropped, if the queue is full.
+func (sm *Manager) NewSearch(sr Request) (string, error) {
 	sm.Lock()
 	defer sm.Unlock()
 
 	ID := ulid.New()
 	sm.List[ID] = &Search{
-		ID:      ID,
-		Input:   sr.Input,
-		Repo:    sr.Repo,
-		Private: sr.Private,
-		Options: &sr.Opts,
-		Status:  Queued,
+		ID:        ID,
+		Input:     sr.Input,
+		Repo:      sr.Repo,
+		Repos:     sr.Repos,
+		Private:   sr.Private,
+		Options:   &sr.Opts,
+		Status:    Queued,
+		TokenHash: hashToken(sr.Token),
+		WatchID:   sr.WatchID,
+	}
+	sm.streams[ID] = newStream()
+	sm.jobs[ID] = newJob()
+
+	if err := sm.Queue.Add(ID); err != nil {
+		delete(sm.List, ID)
+		delete(sm.streams, ID)
+		delete(sm.jobs, ID)
+		return "", err
 	}
 
-	sm.Queue.Add(ID)
+	return ID, nil
+}
 
-	return ID
+// StartWorkers starts Goroutines which check the Search queue and process
+// Searches, allowing several Searches to be processed at once
+func (sm *Manager) StartWorkers() {
+	for i := 0; i < sm.concurrent; i++ {
+		go sm.worker()
+	}
 }
 
-// Worker checks the Search queue and processes Searches
-// Only one Search can be processed at once
-func (sm *Manager) Worker() {
+// worker checks the Search queue and processes Searches
+func (sm *Manager) worker() {
 
 	for {
 		searchID := sm.Queue.Get()
 		err := sm.processSearch(searchID)
 		if err != nil {
 			log.Printf("Searched failed: %s\n", err)
+			sm.Lock()
+			if srch, ok := sm.List[searchID]; ok {
+				srch.Status = Failed
+			}
+			sm.Unlock()
+			sm.closeStream(searchID, &Event{
+				Type:   EventCompleted,
+				Status: Failed,
+			})
+			// Let a Watch run the Search again
+			if sm.Exists(searchID) {
+				sm.finishWatch(sm.Get(searchID))
+			}
 		}
 	}
 
 }
 
 // SummaryList contains the Search Summary
+// Order holds the keys of the Results in the order they were found.
 type SummaryList struct {
 	List  map[string]*Result
+	Order []string
 	Total uint64
 	sync.RWMutex
 }
@@ -129,17 +238,22 @@ func (sm *Manager) processSearch(ID string) error {
 
 	sm.RLock()
 	srch, ok := sm.List[ID]
+	st := sm.streams[ID]
+	j := sm.jobs[ID]
 	sm.RUnlock()
 	if !ok {
 		return errors.New("No search found")
 	}
 
-	opts := &index.SearchOptions{
-		Offset:         0,
-		Limit:          0,
-		LinesOfContext: 2,
-		IgnoreCase:     false,
-		IgnoreComments: false,
+	ctx := context.Background()
+	if j != nil {
+		ctx = j.ctx
+	}
+	// Stop long running Searches, keeping the results found so far
+	if sm.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sm.timeout)
+		defer cancel()
 	}
 
 	var total, current, totalMatches uint64
@@ -148,19 +262,19 @@ func (sm *Manager) processSearch(ID string) error {
 	sm.RLock()
 	input = srch.Input
 	searchID := srch.ID
+	opts := indexOptions(srch.Options)
+	var version string
+	if srch.Options != nil {
+		version = srch.Options.Version
+	}
 	sm.RUnlock()
+	// Matches are marked with their version when past versions are searched
+	tagVersions := version != "" && version != repo.VersionLatest
 
 	sm.Lock()
 	srch.Started = time.Now().Format(time.RFC3339)
 	srch.Status = Started
 	srch.Matches = 0
-	srch.Options = &Options{
-		IgnoreCase:     opts.IgnoreCase,
-		LinesOfContext: uint32(opts.LinesOfContext),
-		IgnoreComments: opts.IgnoreComments,
-		Offset:         uint32(opts.Offset),
-		Limit:          uint32(opts.Limit),
-	}
 	sm.Unlock()
 
 	sum := &SummaryList{
@@ -171,91 +285,121 @@ func (sm *Manager) processSearch(ID string) error {
 		List: make(map[string]*Matches),
 	}
 
-	limiter := make(chan struct{}, sm.limit)
+	// Share the Extension search budget with other running Searches
+	sm.budget.join(searchID)
+	defer sm.budget.leave(searchID)
 	var wg sync.WaitGroup
 
-	// Select the relevant Repository
-	var r *repo.Repo
-	switch srch.Repo {
-	case "plugins":
-		r = sm.Plugins
-		break
-	case "themes":
-		r = sm.Themes
-		break
-	default:
-		return errors.New("Not a valid repository name")
-	}
-
-	r.RLock()
-	total = uint64(len(r.List))
-	list := r.List
-	revision := uint32(r.Revision)
-	r.RUnlock()
-	for _, e := range list {
-		// Limit to 100000 matches
-		if totalMatches > 100000 {
-			break
-		}
-		current++
-		sm.Lock()
-		srch.Progress = uint32(math.Round((float64(current) / float64(total)) * 100.00))
-		srch.Matches = uint32(totalMatches)
-		sm.Unlock()
-		if e.Status != repo.Open {
-			continue
+	// Select the relevant Repositories and the Extensions within them
+	// matching the filters, before any index is opened
+	var filters *Filters
+	if srch.Options != nil {
+		filters = srch.Options.Filters
+	}
+	flt, err := newFilter(filters)
+	if err != nil {
+		return err
+	}
+
+	names := srch.RepoNames()
+	lists := make([][]*repo.Extension, len(names))
+	revisions := make(map[string]uint32, len(names))
+	for i, name := range names {
+		r := sm.Repo(name)
+		if r == nil {
+			return errors.New("Not a valid repository name")
 		}
-		wg.Add(1)
-		limiter <- struct{}{}
-
-		go func(e *repo.Extension, input string, sum *SummaryList, matchlist *MatchList, totalMatches *uint64, wg *sync.WaitGroup) {
-			e.RLock()
-			defer e.RUnlock()
-			resp, err := e.Search(input, e.Slug, opts)
-			if err != nil || len(resp.Matches) == 0 {
-				wg.Done()
-				<-limiter
-				return
+		lists[i] = flt.extensions(r)
+		total += uint64(len(lists[i]))
+
+		r.RLock()
+		revisions[name] = uint32(r.Revision)
+		r.RUnlock()
+	}
+
+	sm.Lock()
+	srch.Revision = revisions[names[0]]
+	srch.Revisions = revisions
+	sm.Unlock()
+
+	for i, list := range lists {
+		repoName := names[i]
+		for _, e := range list {
+			// Limit to 100000 matches
+			if totalMatches > 100000 {
+				break
 			}
-			var eMatches uint64
-			for i := 0; i < len(resp.Matches); i++ {
-				if resp.Matches[i].Matches == nil {
-					continue
+			// Stop if the Search has been cancelled
+			if ctx.Err() != nil {
+				break
+			}
+			current++
+			sm.Lock()
+			srch.Progress = uint32(math.Round((float64(current) / float64(total)) * 100.00))
+			srch.Matches = uint32(totalMatches)
+			sm.Unlock()
+			if e.Status != repo.Open {
+				continue
+			}
+			wg.Add(1)
+			sm.budget.acquire(searchID)
+
+			go func(e *repo.Extension, repoName, input string, sum *SummaryList, matchlist *MatchList, totalMatches *uint64, wg *sync.WaitGroup) {
+				e.RLock()
+				defer e.RUnlock()
+				if ctx.Err() != nil {
+					wg.Done()
+					sm.budget.release(searchID)
+					return
 				}
-				eMatches = uint64(len(resp.Matches[i].Matches))
-				atomic.AddUint64(totalMatches, eMatches)
-				ms := &Matches{}
-				for j := 0; j < len(resp.Matches[i].Matches); j++ {
-					text := resp.Matches[i].Matches[j].Line
-					if len(text) > 100 {
-						text = text[0:100]
-					}
-					m := &Match{
-						Slug:     e.Slug,
-						File:     resp.Matches[i].Filename,
-						LineNum:  uint32(resp.Matches[i].Matches[j].LineNumber),
-						LineText: text,
-					}
-					ms.List = append(ms.List, m)
+				key := srch.ResultKey(repoName, e.Slug)
+				// Partial results are kept if the Search is cancelled
+				resps, err := e.SearchVersions(ctx, input, e.Slug, version, opts)
+				if err != nil && err != context.Canceled && err != context.DeadlineExceeded {
+					log.Printf("Search %s failed for %s: %s\n", searchID, key, err)
+					metrics.SearchErrors.Inc()
 				}
+				ms, versions, filesWithMatch := collectMatches(e.Slug, resps, sm.lineLength, tagVersions)
+				if len(ms.List) == 0 {
+					wg.Done()
+					sm.budget.release(searchID)
+					return
+				}
+				eMatches := uint64(len(ms.List))
+				atomic.AddUint64(totalMatches, eMatches)
 				matchList.Lock()
-				matchList.List[e.Slug] = ms
+				matchList.List[key] = ms
 				matchList.Unlock()
-			}
-			r := &Result{
-				Slug:           e.Slug,
-				Name:           e.Name,
-				Version:        e.Version,
-				Homepage:       e.Homepage,
-				ActiveInstalls: uint32(e.ActiveInstalls),
-				Matches:        uint32(eMatches),
-			}
-			sum.Lock()
-			sum.List[e.Slug] = r
-			sum.Unlock()
-			wg.Done()
-			<-limiter
-		}(e, input, sum, matchList, &totalMatches, &wg)
+
+				r := &Result{
+					Slug:           e.Slug,
+					Name:           e.Name,
+					Version:        e.Version,
+					Homepage:       e.Homepage,
+					ActiveInstalls: uint32(e.ActiveInstalls),
+					Matches:        uint32(eMatches),
+					Repo:           repoName,
+					FilesWithMatch: uint32(filesWithMatch),
+					Versions:       versions,
+				}
+				// Results are published in the order they are saved, so the
+				// Events replayed from the DB keep the same IDs
+				sum.Lock()
+				sum.List[key] = r
+				sum.Order = append(sum.Order, key)
+				if st != nil {
+					st.publish(&Event{
+						Type:    EventResult,
+						Result:  r,
+						Matches: ms,
+						Status:  Started,
+					})
+				}
+				sum.Unlock()
+				wg.Done()
+				sm.budget.release(searchID)
+			}(e, repoName, input, sum, matchList, &totalMatches, &wg)
+		}
 	}
 
 	wg.Wait()
@@ -276,6 +420,14 @@ func (sm *Manager) processSearch(ID string) error {
 	if err != nil {
 		return errors.New("Failed Saving Summary to DB")
 	}
+	bytes, err = json.Marshal(sum.Order)
+	if err != nil {
+		return errors.New("Failed Marshalling Result Order")
+	}
+	err = db.SaveResultOrder(searchID, bytes)
+	if err != nil {
+		return errors.New("Failed Saving Result Order to DB")
+	}
 
 	// Create new map with Marshal bytes so that the DB can be run as a transaction
 	mlist := make(map[string][]byte, len(matchList.List))
@@ -291,11 +443,18 @@ func (sm *Manager) processSearch(ID string) error {
 		return errors.New("Failed Saving Summary to DB")
 	}
 
+	status := Completed
+	switch ctx.Err() {
+	case context.Canceled:
+		status = Cancelled
+	case context.DeadlineExceeded:
+		status = TimedOut
+	}
+
 	sm.Lock()
 	srch.Completed = time.Now().Format(time.RFC3339)
-	srch.Status = Completed
+	srch.Status = status
 	srch.Matches = uint32(totalMatches)
-	srch.Revision = revision
 	sm.Unlock()
 
 	sm.RLock()
@@ -311,8 +470,20 @@ func (sm *Manager) processSearch(ID string) error {
 		return errors.New("Failed Saving Search to DB")
 	}
 
+	// Let clients know the Search has completed
+	sm.closeStream(searchID, &Event{
+		Type:   EventCompleted,
+		Status: status,
+		Total:  uint32(totalMatches),
+	})
+
+	// Compare with the previous run if started by a Watch
+	sm.finishWatch(s)
+
+	webhook.Send(webhook.SearchCompleted, &s)
+
 	// Delete from Memory once saved in DB
-	delete(sm.List, searchID)
+	sm.remove(searchID)
 
 	// Update Metrics
 	metrics.SearchCount.Inc()
@@ -324,13 +495,35 @@ func (sm *Manager) processSearch(ID string) error {
 	return nil
 }
 
+// indexOptions converts the stored Search Options into the options used
+// when searching an Extension index
+func indexOptions(o *Options) *index.SearchOptions {
+	if o == nil {
+		o = &Options{}
+	}
+
+	return &index.SearchOptions{
+		IgnoreCase:     o.IgnoreCase,
+		LinesOfContext: uint(o.LinesOfContext),
+		FileRegexp:     o.FileRegexp,
+		IgnoreComments: o.IgnoreComments,
+		Offset:         int(o.Offset),
+		Limit:          int(o.Limit),
+		Mode:           o.Mode,
+		Scope:          o.Scope,
+	}
+}
+
 // Request contains a Search request
 type Request struct {
 	Input   string
 	Repo    string
+	Repos   []string
 	Private bool
 	Time    time.Time
 	Opts    Options
+	Token   string
+	WatchID string
 }
 
 // Search struct auto-generated into search.pb.go
@@ -33,41 +33,54 @@ const (
 	Queued    Search_Status = 0
 	Started   Search_Status = 1
 	Completed Search_Status = 2
+	Cancelled Search_Status = 3
+	TimedOut  Search_Status = 4
+	Failed    Search_Status = 5
 )
 
 var Search_Status_name = map[int32]string{
 	0: "Queued",
 	1: "Started",
 	2: "Completed",
+	3: "Cancelled",
+	4: "TimedOut",
+	5: "Failed",
 }
 var Search_Status_value = map[string]int32{
 	"Queued":    0,
 	"Started":   1,
 	"Completed": 2,
+	"Cancelled": 3,
+	"TimedOut":  4,
+	"Failed":    5,
 }
 
 func (Search_Status) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_search_cba70e344642d923, []int{0, 0}
+	return fileDescriptor_search_627ba492b0d2b684, []int{0, 0}
 }
 
 type Search struct {
-	ID        string        `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Input     string        `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`
-	Repo      string        `protobuf:"bytes,3,opt,name=repo,proto3" json:"repo,omitempty"`
-	Started   string        `protobuf:"bytes,4,opt,name=started,proto3" json:"started,omitempty"`
-	Completed string        `protobuf:"bytes,5,opt,name=completed,proto3" json:"completed,omitempty"`
-	Progress  uint32        `protobuf:"varint,6,opt,name=progress,proto3" json:"progress,omitempty"`
-	Private   bool          `protobuf:"varint,7,opt,name=private,proto3" json:"private,omitempty"`
-	Status    Search_Status `protobuf:"varint,8,opt,name=status,proto3,enum=search.Search_Status" json:"status,omitempty"`
-	Options   *Options      `protobuf:"bytes,9,opt,name=options" json:"options,omitempty"`
-	Matches   uint32        `protobuf:"varint,10,opt,name=matches,proto3" json:"matches"`
-	Revision  uint32        `protobuf:"varint,11,opt,name=revision,proto3" json:"revision,omitempty"`
+	ID        string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Input     string            `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`
+	Repo      string            `protobuf:"bytes,3,opt,name=repo,proto3" json:"repo,omitempty"`
+	Started   string            `protobuf:"bytes,4,opt,name=started,proto3" json:"started,omitempty"`
+	Completed string            `protobuf:"bytes,5,opt,name=completed,proto3" json:"completed,omitempty"`
+	Progress  uint32            `protobuf:"varint,6,opt,name=progress,proto3" json:"progress,omitempty"`
+	Private   bool              `protobuf:"varint,7,opt,name=private,proto3" json:"private,omitempty"`
+	Status    Search_Status     `protobuf:"varint,8,opt,name=status,proto3,enum=search.Search_Status" json:"status,omitempty"`
+	Options   *Options          `protobuf:"bytes,9,opt,name=options" json:"options,omitempty"`
+	Matches   uint32            `protobuf:"varint,10,opt,name=matches,proto3" json:"matches"`
+	Revision  uint32            `protobuf:"varint,11,opt,name=revision,proto3" json:"revision,omitempty"`
+	TokenHash string            `protobuf:"bytes,12,opt,name=token_hash,json=tokenHash,proto3" json:"-"`
+	Repos     []string          `protobuf:"bytes,13,rep,name=repos" json:"repos,omitempty"`
+	Revisions map[string]uint32 `protobuf:"bytes,14,rep,name=revisions" json:"revisions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	WatchID   string            `protobuf:"bytes,15,opt,name=watch_id,json=watchId,proto3" json:"watch_id,omitempty"`
 }
 
 func (m *Search) Reset()      { *m = Search{} }
 func (*Search) ProtoMessage() {}
 func (*Search) Descriptor() ([]byte, []int) {
-	return fileDescriptor_search_cba70e344642d923, []int{0}
+	return fileDescriptor_search_627ba492b0d2b684, []int{0}
 }
 func (m *Search) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -97,18 +110,23 @@ func (m *Search) XXX_DiscardUnknown() {
 var xxx_messageInfo_Search proto.InternalMessageInfo
 
 type Options struct {
-	IgnoreCase     bool   `protobuf:"varint,1,opt,name=ignore_case,json=ignoreCase,proto3" json:"ignore_case,omitempty"`
-	LinesOfContext uint32 `protobuf:"varint,2,opt,name=lines_of_context,json=linesOfContext,proto3" json:"lines_of_context,omitempty"`
-	FileRegexp     string `protobuf:"bytes,3,opt,name=file_regexp,json=fileRegexp,proto3" json:"file_regexp,omitempty"`
-	IgnoreComments bool   `protobuf:"varint,4,opt,name=ignore_comments,json=ignoreComments,proto3" json:"ignore_comments,omitempty"`
-	Offset         uint32 `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
-	Limit          uint32 `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	IgnoreCase     bool     `protobuf:"varint,1,opt,name=ignore_case,json=ignoreCase,proto3" json:"ignore_case,omitempty"`
+	LinesOfContext uint32   `protobuf:"varint,2,opt,name=lines_of_context,json=linesOfContext,proto3" json:"lines_of_context,omitempty"`
+	FileRegexp     string   `protobuf:"bytes,3,opt,name=file_regexp,json=fileRegexp,proto3" json:"file_regexp,omitempty"`
+	IgnoreComments bool     `protobuf:"varint,4,opt,name=ignore_comments,json=ignoreComments,proto3" json:"ignore_comments,omitempty"`
+	Offset         uint32   `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit          uint32   `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Mode           string   `protobuf:"bytes,7,opt,name=mode,proto3" json:"mode,omitempty"`
+	Scope          string   `protobuf:"bytes,8,opt,name=scope,proto3" json:"scope,omitempty"`
+	Filters        *Filters `protobuf:"bytes,9,opt,name=filters" json:"filters,omitempty"`
+	// version selects the indexes searched: latest, all or a version
+	Version string `protobuf:"bytes,10,opt,name=version,proto3" json:"version,omitempty"`
 }
 
 func (m *Options) Reset()      { *m = Options{} }
 func (*Options) ProtoMessage() {}
 func (*Options) Descriptor() ([]byte, []int) {
-	return fileDescriptor_search_cba70e344642d923, []int{1}
+	return fileDescriptor_search_627ba492b0d2b684, []int{1}
 }
 func (m *Options) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -137,6 +155,51 @@ func (m *Options) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Options proto.InternalMessageInfo
 
+type Filters struct {
+	Slugs         []string `protobuf:"bytes,1,rep,name=slugs" json:"slugs,omitempty"`
+	Author        string   `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Tags          []string `protobuf:"bytes,3,rep,name=tags" json:"tags,omitempty"`
+	MinInstalls   uint32   `protobuf:"varint,4,opt,name=min_installs,json=minInstalls,proto3" json:"min_installs,omitempty"`
+	MaxInstalls   uint32   `protobuf:"varint,5,opt,name=max_installs,json=maxInstalls,proto3" json:"max_installs,omitempty"`
+	RequiresPHP   string   `protobuf:"bytes,6,opt,name=requires_php,json=requiresPhp,proto3" json:"requires_php,omitempty"`
+	TestedMin     string   `protobuf:"bytes,7,opt,name=tested_min,json=testedMin,proto3" json:"tested_min,omitempty"`
+	TestedMax     string   `protobuf:"bytes,8,opt,name=tested_max,json=testedMax,proto3" json:"tested_max,omitempty"`
+	UpdatedAfter  string   `protobuf:"bytes,9,opt,name=updated_after,json=updatedAfter,proto3" json:"updated_after,omitempty"`
+	UpdatedBefore string   `protobuf:"bytes,10,opt,name=updated_before,json=updatedBefore,proto3" json:"updated_before,omitempty"`
+}
+
+func (m *Filters) Reset()      { *m = Filters{} }
+func (*Filters) ProtoMessage() {}
+func (*Filters) Descriptor() ([]byte, []int) {
+	return fileDescriptor_search_627ba492b0d2b684, []int{2}
+}
+func (m *Filters) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Filters) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Filters.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *Filters) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Filters.Merge(dst, src)
+}
+func (m *Filters) XXX_Size() int {
+	return m.Size()
+}
+func (m *Filters) XXX_DiscardUnknown() {
+	xxx_messageInfo_Filters.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Filters proto.InternalMessageInfo
+
 type Summary struct {
 	Total uint64             `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
 	List  map[string]*Result `protobuf:"bytes,2,rep,name=list" json:"list,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
@@ -145,7 +208,7 @@ type Summary struct {
 func (m *Summary) Reset()      { *m = Summary{} }
 func (*Summary) ProtoMessage() {}
 func (*Summary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_search_cba70e344642d923, []int{2}
+	return fileDescriptor_search_627ba492b0d2b684, []int{3}
 }
 func (m *Summary) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -181,12 +244,16 @@ type Result struct {
 	Homepage       string `protobuf:"bytes,4,opt,name=homepage,proto3" json:"homepage,omitempty"`
 	ActiveInstalls uint32 `protobuf:"varint,5,opt,name=active_installs,json=activeInstalls,proto3" json:"active_installs"`
 	Matches        uint32 `protobuf:"varint,6,opt,name=matches,proto3" json:"matches"`
+	Repo           string `protobuf:"bytes,7,opt,name=repo,proto3" json:"repo,omitempty"`
+	FilesWithMatch uint32 `protobuf:"varint,8,opt,name=files_with_match,json=filesWithMatch,proto3" json:"files_with_match"`
+	// versions lists the versions with matches, when searching past versions
+	Versions []string `protobuf:"bytes,9,rep,name=versions" json:"versions,omitempty"`
 }
 
 func (m *Result) Reset()      { *m = Result{} }
 func (*Result) ProtoMessage() {}
 func (*Result) Descriptor() ([]byte, []int) {
-	return fileDescriptor_search_cba70e344642d923, []int{3}
+	return fileDescriptor_search_627ba492b0d2b684, []int{4}
 }
 func (m *Result) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -216,13 +283,15 @@ func (m *Result) XXX_DiscardUnknown() {
 var xxx_messageInfo_Result proto.InternalMessageInfo
 
 type Matches struct {
-	List []*Match `protobuf:"bytes,1,rep,name=list" json:"list,omitempty"`
+	// list holds the Matches of every file, grouped by file
+	List  []*Match     `protobuf:"bytes,1,rep,name=list" json:"list,omitempty"`
+	Files []*FileCount `protobuf:"bytes,2,rep,name=files" json:"files,omitempty"`
 }
 
 func (m *Matches) Reset()      { *m = Matches{} }
 func (*Matches) ProtoMessage() {}
 func (*Matches) Descriptor() ([]byte, []int) {
-	return fileDescriptor_search_cba70e344642d923, []int{4}
+	return fileDescriptor_search_627ba492b0d2b684, []int{5}
 }
 func (m *Matches) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -251,17 +320,65 @@ func (m *Matches) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Matches proto.InternalMessageInfo
 
+type FileCount struct {
+	File    string `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Matches uint32 `protobuf:"varint,2,opt,name=matches,proto3" json:"matches"`
+	Version string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (m *FileCount) Reset()      { *m = FileCount{} }
+func (*FileCount) ProtoMessage() {}
+func (*FileCount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_search_627ba492b0d2b684, []int{6}
+}
+func (m *FileCount) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FileCount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FileCount.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *FileCount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileCount.Merge(dst, src)
+}
+func (m *FileCount) XXX_Size() int {
+	return m.Size()
+}
+func (m *FileCount) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileCount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FileCount proto.InternalMessageInfo
+
 type Match struct {
-	Slug     string `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
-	File     string `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
-	LineNum  uint32 `protobuf:"varint,3,opt,name=line_num,json=lineNum,proto3" json:"line_num,omitempty"`
-	LineText string `protobuf:"bytes,4,opt,name=line_text,json=lineText,proto3" json:"line_text,omitempty"`
+	Slug     string   `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	File     string   `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	LineNum  uint32   `protobuf:"varint,3,opt,name=line_num,json=lineNum,proto3" json:"line_num,omitempty"`
+	LineText string   `protobuf:"bytes,4,opt,name=line_text,json=lineText,proto3" json:"line_text,omitempty"`
+	Before   []string `protobuf:"bytes,5,rep,name=before" json:"before,omitempty"`
+	After    []string `protobuf:"bytes,6,rep,name=after" json:"after,omitempty"`
+	// match_start and match_end are the byte offsets of the match in line_text
+	MatchStart uint32 `protobuf:"varint,7,opt,name=match_start,json=matchStart,proto3" json:"match_start"`
+	MatchEnd   uint32 `protobuf:"varint,8,opt,name=match_end,json=matchEnd,proto3" json:"match_end"`
+	// text_offset is the byte offset of line_text in the original line
+	TextOffset uint32 `protobuf:"varint,9,opt,name=text_offset,json=textOffset,proto3" json:"text_offset,omitempty"`
+	Truncated  bool   `protobuf:"varint,10,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	Repo       string `protobuf:"bytes,11,opt,name=repo,proto3" json:"repo,omitempty"`
+	Version    string `protobuf:"bytes,12,opt,name=version,proto3" json:"version,omitempty"`
 }
 
 func (m *Match) Reset()      { *m = Match{} }
 func (*Match) ProtoMessage() {}
 func (*Match) Descriptor() ([]byte, []int) {
-	return fileDescriptor_search_cba70e344642d923, []int{5}
+	return fileDescriptor_search_627ba492b0d2b684, []int{7}
 }
 func (m *Match) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -290,14 +407,155 @@ func (m *Match) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Match proto.InternalMessageInfo
 
+type Watch struct {
+	ID      string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name    string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Input   string   `protobuf:"bytes,3,opt,name=input,proto3" json:"input,omitempty"`
+	Repo    string   `protobuf:"bytes,4,opt,name=repo,proto3" json:"repo,omitempty"`
+	Repos   []string `protobuf:"bytes,5,rep,name=repos" json:"repos,omitempty"`
+	Options *Options `protobuf:"bytes,6,opt,name=options" json:"options,omitempty"`
+	// interval is the number of seconds between runs
+	Interval int64  `protobuf:"varint,7,opt,name=interval,proto3" json:"interval,omitempty"`
+	Created  string `protobuf:"bytes,8,opt,name=created,proto3" json:"created,omitempty"`
+	LastRun  string `protobuf:"bytes,9,opt,name=last_run,json=lastRun,proto3" json:"last_run,omitempty"`
+	// last_search is the most recent completed Search changes are found from
+	LastSearch string `protobuf:"bytes,10,opt,name=last_search,json=lastSearch,proto3" json:"last_search,omitempty"`
+	// pending_search is a Search which has been started but not finished
+	PendingSearch string `protobuf:"bytes,11,opt,name=pending_search,json=pendingSearch,proto3" json:"pending_search,omitempty"`
+}
+
+func (m *Watch) Reset()      { *m = Watch{} }
+func (*Watch) ProtoMessage() {}
+func (*Watch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_search_627ba492b0d2b684, []int{8}
+}
+func (m *Watch) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Watch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Watch.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *Watch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Watch.Merge(dst, src)
+}
+func (m *Watch) XXX_Size() int {
+	return m.Size()
+}
+func (m *Watch) XXX_DiscardUnknown() {
+	xxx_messageInfo_Watch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Watch proto.InternalMessageInfo
+
+type WatchChanges struct {
+	WatchID    string   `protobuf:"bytes,1,opt,name=watch_id,json=watchId,proto3" json:"watch_id,omitempty"`
+	SearchID   string   `protobuf:"bytes,2,opt,name=search_id,json=searchId,proto3" json:"search_id,omitempty"`
+	PreviousID string   `protobuf:"bytes,3,opt,name=previous_id,json=previousId,proto3" json:"previous_id,omitempty"`
+	Created    string   `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
+	Added      []*Match `protobuf:"bytes,5,rep,name=added" json:"added,omitempty"`
+	Removed    []*Match `protobuf:"bytes,6,rep,name=removed" json:"removed,omitempty"`
+}
+
+func (m *WatchChanges) Reset()      { *m = WatchChanges{} }
+func (*WatchChanges) ProtoMessage() {}
+func (*WatchChanges) Descriptor() ([]byte, []int) {
+	return fileDescriptor_search_627ba492b0d2b684, []int{9}
+}
+func (m *WatchChanges) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WatchChanges) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WatchChanges.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *WatchChanges) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchChanges.Merge(dst, src)
+}
+func (m *WatchChanges) XXX_Size() int {
+	return m.Size()
+}
+func (m *WatchChanges) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchChanges.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchChanges proto.InternalMessageInfo
+
+// WatchEvent records new Matches of a Watch found when an Extension updated
+type WatchEvent struct {
+	ID         string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	WatchID    string   `protobuf:"bytes,2,opt,name=watch_id,json=watchId,proto3" json:"watch_id,omitempty"`
+	Repo       string   `protobuf:"bytes,3,opt,name=repo,proto3" json:"repo,omitempty"`
+	Slug       string   `protobuf:"bytes,4,opt,name=slug,proto3" json:"slug,omitempty"`
+	OldVersion string   `protobuf:"bytes,5,opt,name=old_version,json=oldVersion,proto3" json:"old_version,omitempty"`
+	NewVersion string   `protobuf:"bytes,6,opt,name=new_version,json=newVersion,proto3" json:"new_version,omitempty"`
+	Pattern    string   `protobuf:"bytes,7,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Created    string   `protobuf:"bytes,8,opt,name=created,proto3" json:"created,omitempty"`
+	Matches    []*Match `protobuf:"bytes,9,rep,name=matches" json:"matches,omitempty"`
+}
+
+func (m *WatchEvent) Reset()      { *m = WatchEvent{} }
+func (*WatchEvent) ProtoMessage() {}
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_search_627ba492b0d2b684, []int{10}
+}
+func (m *WatchEvent) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WatchEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WatchEvent.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *WatchEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchEvent.Merge(dst, src)
+}
+func (m *WatchEvent) XXX_Size() int {
+	return m.Size()
+}
+func (m *WatchEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchEvent proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*Search)(nil), "search.Search")
+	proto.RegisterMapType((map[string]uint32)(nil), "search.Search.RevisionsEntry")
 	proto.RegisterType((*Options)(nil), "search.Options")
+	proto.RegisterType((*Filters)(nil), "search.Filters")
 	proto.RegisterType((*Summary)(nil), "search.Summary")
 	proto.RegisterMapType((map[string]*Result)(nil), "search.Summary.ListEntry")
 	proto.RegisterType((*Result)(nil), "search.Result")
 	proto.RegisterType((*Matches)(nil), "search.Matches")
+	proto.RegisterType((*FileCount)(nil), "search.FileCount")
 	proto.RegisterType((*Match)(nil), "search.Match")
+	proto.RegisterType((*Watch)(nil), "search.Watch")
+	proto.RegisterType((*WatchChanges)(nil), "search.WatchChanges")
+	proto.RegisterType((*WatchEvent)(nil), "search.WatchEvent")
 	proto.RegisterEnum("search.Search_Status", Search_Status_name, Search_Status_value)
 }
 func (x Search_Status) String() string {
@@ -392,6 +650,49 @@ func (m *Search) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintSearch(dAtA, i, uint64(m.Revision))
 	}
+	if len(m.TokenHash) > 0 {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.TokenHash)))
+		i += copy(dAtA[i:], m.TokenHash)
+	}
+	if len(m.Repos) > 0 {
+		for _, s := range m.Repos {
+			dAtA[i] = 0x6a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Revisions) > 0 {
+		for k, _ := range m.Revisions {
+			dAtA[i] = 0x72
+			i++
+			v := m.Revisions[k]
+			mapSize := 1 + len(k) + sovSearch(uint64(len(k))) + 1 + sovSearch(uint64(v))
+			i = encodeVarintSearch(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintSearch(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x10
+			i++
+			i = encodeVarintSearch(dAtA, i, uint64(v))
+		}
+	}
+	if len(m.WatchID) > 0 {
+		dAtA[i] = 0x7a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.WatchID)))
+		i += copy(dAtA[i:], m.WatchID)
+	}
 	return i, nil
 }
 
@@ -451,6 +752,128 @@ func (m *Options) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintSearch(dAtA, i, uint64(m.Limit))
 	}
+	if len(m.Mode) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Mode)))
+		i += copy(dAtA[i:], m.Mode)
+	}
+	if len(m.Scope) > 0 {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Scope)))
+		i += copy(dAtA[i:], m.Scope)
+	}
+	if m.Filters != nil {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(m.Filters.Size()))
+		n2, err := m.Filters.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if len(m.Version) > 0 {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Version)))
+		i += copy(dAtA[i:], m.Version)
+	}
+	return i, nil
+}
+
+func (m *Filters) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Filters) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Slugs) > 0 {
+		for _, s := range m.Slugs {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Author) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Author)))
+		i += copy(dAtA[i:], m.Author)
+	}
+	if len(m.Tags) > 0 {
+		for _, s := range m.Tags {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.MinInstalls != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(m.MinInstalls))
+	}
+	if m.MaxInstalls != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(m.MaxInstalls))
+	}
+	if len(m.RequiresPHP) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.RequiresPHP)))
+		i += copy(dAtA[i:], m.RequiresPHP)
+	}
+	if len(m.TestedMin) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.TestedMin)))
+		i += copy(dAtA[i:], m.TestedMin)
+	}
+	if len(m.TestedMax) > 0 {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.TestedMax)))
+		i += copy(dAtA[i:], m.TestedMax)
+	}
+	if len(m.UpdatedAfter) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.UpdatedAfter)))
+		i += copy(dAtA[i:], m.UpdatedAfter)
+	}
+	if len(m.UpdatedBefore) > 0 {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.UpdatedBefore)))
+		i += copy(dAtA[i:], m.UpdatedBefore)
+	}
 	return i, nil
 }
 
@@ -494,11 +917,11 @@ func (m *Summary) MarshalTo(dAtA []byte) (int, error) {
 				dAtA[i] = 0x12
 				i++
 				i = encodeVarintSearch(dAtA, i, uint64(v.Size()))
-				n2, err := v.MarshalTo(dAtA[i:])
+				n3, err := v.MarshalTo(dAtA[i:])
 				if err != nil {
 					return 0, err
 				}
-				i += n2
+				i += n3
 			}
 		}
 	}
@@ -554,6 +977,32 @@ func (m *Result) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintSearch(dAtA, i, uint64(m.Matches))
 	}
+	if len(m.Repo) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Repo)))
+		i += copy(dAtA[i:], m.Repo)
+	}
+	if m.FilesWithMatch != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(m.FilesWithMatch))
+	}
+	if len(m.Versions) > 0 {
+		for _, s := range m.Versions {
+			dAtA[i] = 0x4a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
 	return i, nil
 }
 
@@ -584,6 +1033,53 @@ func (m *Matches) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.Files) > 0 {
+		for _, msg := range m.Files {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintSearch(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *FileCount) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileCount) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.File) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.File)))
+		i += copy(dAtA[i:], m.File)
+	}
+	if m.Matches != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(m.Matches))
+	}
+	if len(m.Version) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Version)))
+		i += copy(dAtA[i:], m.Version)
+	}
 	return i, nil
 }
 
@@ -625,17 +1121,324 @@ func (m *Match) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintSearch(dAtA, i, uint64(len(m.LineText)))
 		i += copy(dAtA[i:], m.LineText)
 	}
-	return i, nil
-}
-
-func encodeVarintSearch(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	if len(m.Before) > 0 {
+		for _, s := range m.Before {
+			dAtA[i] = 0x2a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.After) > 0 {
+		for _, s := range m.After {
+			dAtA[i] = 0x32
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.MatchStart != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(m.MatchStart))
+	}
+	if m.MatchEnd != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(m.MatchEnd))
+	}
+	if m.TextOffset != 0 {
+		dAtA[i] = 0x48
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(m.TextOffset))
+	}
+	if m.Truncated {
+		dAtA[i] = 0x50
+		i++
+		if m.Truncated {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.Repo) > 0 {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Repo)))
+		i += copy(dAtA[i:], m.Repo)
+	}
+	if len(m.Version) > 0 {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Version)))
+		i += copy(dAtA[i:], m.Version)
+	}
+	return i, nil
+}
+
+func (m *Watch) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Watch) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.ID)))
+		i += copy(dAtA[i:], m.ID)
+	}
+	if len(m.Name) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	if len(m.Input) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Input)))
+		i += copy(dAtA[i:], m.Input)
+	}
+	if len(m.Repo) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Repo)))
+		i += copy(dAtA[i:], m.Repo)
+	}
+	if len(m.Repos) > 0 {
+		for _, s := range m.Repos {
+			dAtA[i] = 0x2a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.Options != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(m.Options.Size()))
+		n4, err := m.Options.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if m.Interval != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(m.Interval))
+	}
+	if len(m.Created) > 0 {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Created)))
+		i += copy(dAtA[i:], m.Created)
+	}
+	if len(m.LastRun) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.LastRun)))
+		i += copy(dAtA[i:], m.LastRun)
+	}
+	if len(m.LastSearch) > 0 {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.LastSearch)))
+		i += copy(dAtA[i:], m.LastSearch)
+	}
+	if len(m.PendingSearch) > 0 {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.PendingSearch)))
+		i += copy(dAtA[i:], m.PendingSearch)
+	}
+	return i, nil
+}
+
+func (m *WatchChanges) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WatchChanges) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.WatchID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.WatchID)))
+		i += copy(dAtA[i:], m.WatchID)
+	}
+	if len(m.SearchID) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.SearchID)))
+		i += copy(dAtA[i:], m.SearchID)
+	}
+	if len(m.PreviousID) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.PreviousID)))
+		i += copy(dAtA[i:], m.PreviousID)
+	}
+	if len(m.Created) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Created)))
+		i += copy(dAtA[i:], m.Created)
+	}
+	if len(m.Added) > 0 {
+		for _, msg := range m.Added {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintSearch(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Removed) > 0 {
+		for _, msg := range m.Removed {
+			dAtA[i] = 0x32
+			i++
+			i = encodeVarintSearch(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *WatchEvent) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WatchEvent) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.ID)))
+		i += copy(dAtA[i:], m.ID)
+	}
+	if len(m.WatchID) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.WatchID)))
+		i += copy(dAtA[i:], m.WatchID)
+	}
+	if len(m.Repo) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Repo)))
+		i += copy(dAtA[i:], m.Repo)
+	}
+	if len(m.Slug) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Slug)))
+		i += copy(dAtA[i:], m.Slug)
+	}
+	if len(m.OldVersion) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.OldVersion)))
+		i += copy(dAtA[i:], m.OldVersion)
+	}
+	if len(m.NewVersion) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.NewVersion)))
+		i += copy(dAtA[i:], m.NewVersion)
+	}
+	if len(m.Pattern) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Pattern)))
+		i += copy(dAtA[i:], m.Pattern)
+	}
+	if len(m.Created) > 0 {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintSearch(dAtA, i, uint64(len(m.Created)))
+		i += copy(dAtA[i:], m.Created)
+	}
+	if len(m.Matches) > 0 {
+		for _, msg := range m.Matches {
+			dAtA[i] = 0x4a
+			i++
+			i = encodeVarintSearch(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func encodeVarintSearch(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
 }
 func (m *Search) Size() (n int) {
 	if m == nil {
@@ -682,6 +1485,28 @@ func (m *Search) Size() (n int) {
 	if m.Revision != 0 {
 		n += 1 + sovSearch(uint64(m.Revision))
 	}
+	l = len(m.TokenHash)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	if len(m.Repos) > 0 {
+		for _, s := range m.Repos {
+			l = len(s)
+			n += 1 + l + sovSearch(uint64(l))
+		}
+	}
+	if len(m.Revisions) > 0 {
+		for k, v := range m.Revisions {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovSearch(uint64(len(k))) + 1 + sovSearch(uint64(v))
+			n += mapEntrySize + 1 + sovSearch(uint64(mapEntrySize))
+		}
+	}
+	l = len(m.WatchID)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
 	return n
 }
 
@@ -710,28 +1535,95 @@ func (m *Options) Size() (n int) {
 	if m.Limit != 0 {
 		n += 1 + sovSearch(uint64(m.Limit))
 	}
+	l = len(m.Mode)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.Scope)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	if m.Filters != nil {
+		l = m.Filters.Size()
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
 	return n
 }
 
-func (m *Summary) Size() (n int) {
+func (m *Filters) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.Total != 0 {
-		n += 1 + sovSearch(uint64(m.Total))
+	if len(m.Slugs) > 0 {
+		for _, s := range m.Slugs {
+			l = len(s)
+			n += 1 + l + sovSearch(uint64(l))
+		}
 	}
-	if len(m.List) > 0 {
-		for k, v := range m.List {
-			_ = k
-			_ = v
-			l = 0
-			if v != nil {
-				l = v.Size()
-				l += 1 + sovSearch(uint64(l))
-			}
-			mapEntrySize := 1 + len(k) + sovSearch(uint64(len(k))) + l
+	l = len(m.Author)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	if len(m.Tags) > 0 {
+		for _, s := range m.Tags {
+			l = len(s)
+			n += 1 + l + sovSearch(uint64(l))
+		}
+	}
+	if m.MinInstalls != 0 {
+		n += 1 + sovSearch(uint64(m.MinInstalls))
+	}
+	if m.MaxInstalls != 0 {
+		n += 1 + sovSearch(uint64(m.MaxInstalls))
+	}
+	l = len(m.RequiresPHP)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.TestedMin)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.TestedMax)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.UpdatedAfter)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.UpdatedBefore)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	return n
+}
+
+func (m *Summary) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Total != 0 {
+		n += 1 + sovSearch(uint64(m.Total))
+	}
+	if len(m.List) > 0 {
+		for k, v := range m.List {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovSearch(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovSearch(uint64(len(k))) + l
 			n += mapEntrySize + 1 + sovSearch(uint64(mapEntrySize))
 		}
 	}
@@ -766,6 +1658,19 @@ func (m *Result) Size() (n int) {
 	if m.Matches != 0 {
 		n += 1 + sovSearch(uint64(m.Matches))
 	}
+	l = len(m.Repo)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	if m.FilesWithMatch != 0 {
+		n += 1 + sovSearch(uint64(m.FilesWithMatch))
+	}
+	if len(m.Versions) > 0 {
+		for _, s := range m.Versions {
+			l = len(s)
+			n += 1 + l + sovSearch(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -781,6 +1686,32 @@ func (m *Matches) Size() (n int) {
 			n += 1 + l + sovSearch(uint64(l))
 		}
 	}
+	if len(m.Files) > 0 {
+		for _, e := range m.Files {
+			l = e.Size()
+			n += 1 + l + sovSearch(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *FileCount) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.File)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	if m.Matches != 0 {
+		n += 1 + sovSearch(uint64(m.Matches))
+	}
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
 	return n
 }
 
@@ -805,6 +1736,176 @@ func (m *Match) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovSearch(uint64(l))
 	}
+	if len(m.Before) > 0 {
+		for _, s := range m.Before {
+			l = len(s)
+			n += 1 + l + sovSearch(uint64(l))
+		}
+	}
+	if len(m.After) > 0 {
+		for _, s := range m.After {
+			l = len(s)
+			n += 1 + l + sovSearch(uint64(l))
+		}
+	}
+	if m.MatchStart != 0 {
+		n += 1 + sovSearch(uint64(m.MatchStart))
+	}
+	if m.MatchEnd != 0 {
+		n += 1 + sovSearch(uint64(m.MatchEnd))
+	}
+	if m.TextOffset != 0 {
+		n += 1 + sovSearch(uint64(m.TextOffset))
+	}
+	if m.Truncated {
+		n += 2
+	}
+	l = len(m.Repo)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	return n
+}
+
+func (m *Watch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.Input)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.Repo)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	if len(m.Repos) > 0 {
+		for _, s := range m.Repos {
+			l = len(s)
+			n += 1 + l + sovSearch(uint64(l))
+		}
+	}
+	if m.Options != nil {
+		l = m.Options.Size()
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	if m.Interval != 0 {
+		n += 1 + sovSearch(uint64(m.Interval))
+	}
+	l = len(m.Created)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.LastRun)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.LastSearch)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.PendingSearch)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	return n
+}
+
+func (m *WatchChanges) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.WatchID)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.SearchID)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.PreviousID)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.Created)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	if len(m.Added) > 0 {
+		for _, e := range m.Added {
+			l = e.Size()
+			n += 1 + l + sovSearch(uint64(l))
+		}
+	}
+	if len(m.Removed) > 0 {
+		for _, e := range m.Removed {
+			l = e.Size()
+			n += 1 + l + sovSearch(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *WatchEvent) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.WatchID)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.Repo)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.Slug)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.OldVersion)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.NewVersion)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.Pattern)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	l = len(m.Created)
+	if l > 0 {
+		n += 1 + l + sovSearch(uint64(l))
+	}
+	if len(m.Matches) > 0 {
+		for _, e := range m.Matches {
+			l = e.Size()
+			n += 1 + l + sovSearch(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -825,6 +1926,16 @@ func (this *Search) String() string {
 	if this == nil {
 		return "nil"
 	}
+	keysForRevisions := make([]string, 0, len(this.Revisions))
+	for k, _ := range this.Revisions {
+		keysForRevisions = append(keysForRevisions, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForRevisions)
+	mapStringForRevisions := "map[string]uint32{"
+	for _, k := range keysForRevisions {
+		mapStringForRevisions += fmt.Sprintf("%v: %v,", k, this.Revisions[k])
+	}
+	mapStringForRevisions += "}"
 	s := strings.Join([]string{`&Search{`,
 		`ID:` + fmt.Sprintf("%v", this.ID) + `,`,
 		`Input:` + fmt.Sprintf("%v", this.Input) + `,`,
@@ -837,6 +1948,10 @@ func (this *Search) String() string {
 		`Options:` + strings.Replace(fmt.Sprintf("%v", this.Options), "Options", "Options", 1) + `,`,
 		`Matches:` + fmt.Sprintf("%v", this.Matches) + `,`,
 		`Revision:` + fmt.Sprintf("%v", this.Revision) + `,`,
+		`TokenHash:` + fmt.Sprintf("%v", this.TokenHash) + `,`,
+		`Repos:` + fmt.Sprintf("%v", this.Repos) + `,`,
+		`Revisions:` + mapStringForRevisions + `,`,
+		`WatchID:` + fmt.Sprintf("%v", this.WatchID) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -852,6 +1967,29 @@ func (this *Options) String() string {
 		`IgnoreComments:` + fmt.Sprintf("%v", this.IgnoreComments) + `,`,
 		`Offset:` + fmt.Sprintf("%v", this.Offset) + `,`,
 		`Limit:` + fmt.Sprintf("%v", this.Limit) + `,`,
+		`Mode:` + fmt.Sprintf("%v", this.Mode) + `,`,
+		`Scope:` + fmt.Sprintf("%v", this.Scope) + `,`,
+		`Filters:` + strings.Replace(fmt.Sprintf("%v", this.Filters), "Filters", "Filters", 1) + `,`,
+		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Filters) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Filters{`,
+		`Slugs:` + fmt.Sprintf("%v", this.Slugs) + `,`,
+		`Author:` + fmt.Sprintf("%v", this.Author) + `,`,
+		`Tags:` + fmt.Sprintf("%v", this.Tags) + `,`,
+		`MinInstalls:` + fmt.Sprintf("%v", this.MinInstalls) + `,`,
+		`MaxInstalls:` + fmt.Sprintf("%v", this.MaxInstalls) + `,`,
+		`RequiresPHP:` + fmt.Sprintf("%v", this.RequiresPHP) + `,`,
+		`TestedMin:` + fmt.Sprintf("%v", this.TestedMin) + `,`,
+		`TestedMax:` + fmt.Sprintf("%v", this.TestedMax) + `,`,
+		`UpdatedAfter:` + fmt.Sprintf("%v", this.UpdatedAfter) + `,`,
+		`UpdatedBefore:` + fmt.Sprintf("%v", this.UpdatedBefore) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -888,6 +2026,9 @@ func (this *Result) String() string {
 		`Homepage:` + fmt.Sprintf("%v", this.Homepage) + `,`,
 		`ActiveInstalls:` + fmt.Sprintf("%v", this.ActiveInstalls) + `,`,
 		`Matches:` + fmt.Sprintf("%v", this.Matches) + `,`,
+		`Repo:` + fmt.Sprintf("%v", this.Repo) + `,`,
+		`FilesWithMatch:` + fmt.Sprintf("%v", this.FilesWithMatch) + `,`,
+		`Versions:` + fmt.Sprintf("%v", this.Versions) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -898,6 +2039,19 @@ func (this *Matches) String() string {
 	}
 	s := strings.Join([]string{`&Matches{`,
 		`List:` + strings.Replace(fmt.Sprintf("%v", this.List), "Match", "Match", 1) + `,`,
+		`Files:` + strings.Replace(fmt.Sprintf("%v", this.Files), "FileCount", "FileCount", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *FileCount) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&FileCount{`,
+		`File:` + fmt.Sprintf("%v", this.File) + `,`,
+		`Matches:` + fmt.Sprintf("%v", this.Matches) + `,`,
+		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -906,24 +2060,1931 @@ func (this *Match) String() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&Match{`,
-		`Slug:` + fmt.Sprintf("%v", this.Slug) + `,`,
-		`File:` + fmt.Sprintf("%v", this.File) + `,`,
-		`LineNum:` + fmt.Sprintf("%v", this.LineNum) + `,`,
-		`LineText:` + fmt.Sprintf("%v", this.LineText) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func valueToStringSearch(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+	s := strings.Join([]string{`&Match{`,
+		`Slug:` + fmt.Sprintf("%v", this.Slug) + `,`,
+		`File:` + fmt.Sprintf("%v", this.File) + `,`,
+		`LineNum:` + fmt.Sprintf("%v", this.LineNum) + `,`,
+		`LineText:` + fmt.Sprintf("%v", this.LineText) + `,`,
+		`Before:` + fmt.Sprintf("%v", this.Before) + `,`,
+		`After:` + fmt.Sprintf("%v", this.After) + `,`,
+		`MatchStart:` + fmt.Sprintf("%v", this.MatchStart) + `,`,
+		`MatchEnd:` + fmt.Sprintf("%v", this.MatchEnd) + `,`,
+		`TextOffset:` + fmt.Sprintf("%v", this.TextOffset) + `,`,
+		`Truncated:` + fmt.Sprintf("%v", this.Truncated) + `,`,
+		`Repo:` + fmt.Sprintf("%v", this.Repo) + `,`,
+		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Watch) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Watch{`,
+		`ID:` + fmt.Sprintf("%v", this.ID) + `,`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Input:` + fmt.Sprintf("%v", this.Input) + `,`,
+		`Repo:` + fmt.Sprintf("%v", this.Repo) + `,`,
+		`Repos:` + fmt.Sprintf("%v", this.Repos) + `,`,
+		`Options:` + strings.Replace(fmt.Sprintf("%v", this.Options), "Options", "Options", 1) + `,`,
+		`Interval:` + fmt.Sprintf("%v", this.Interval) + `,`,
+		`Created:` + fmt.Sprintf("%v", this.Created) + `,`,
+		`LastRun:` + fmt.Sprintf("%v", this.LastRun) + `,`,
+		`LastSearch:` + fmt.Sprintf("%v", this.LastSearch) + `,`,
+		`PendingSearch:` + fmt.Sprintf("%v", this.PendingSearch) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *WatchChanges) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&WatchChanges{`,
+		`WatchID:` + fmt.Sprintf("%v", this.WatchID) + `,`,
+		`SearchID:` + fmt.Sprintf("%v", this.SearchID) + `,`,
+		`PreviousID:` + fmt.Sprintf("%v", this.PreviousID) + `,`,
+		`Created:` + fmt.Sprintf("%v", this.Created) + `,`,
+		`Added:` + strings.Replace(fmt.Sprintf("%v", this.Added), "Match", "Match", 1) + `,`,
+		`Removed:` + strings.Replace(fmt.Sprintf("%v", this.Removed), "Match", "Match", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *WatchEvent) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&WatchEvent{`,
+		`ID:` + fmt.Sprintf("%v", this.ID) + `,`,
+		`WatchID:` + fmt.Sprintf("%v", this.WatchID) + `,`,
+		`Repo:` + fmt.Sprintf("%v", this.Repo) + `,`,
+		`Slug:` + fmt.Sprintf("%v", this.Slug) + `,`,
+		`OldVersion:` + fmt.Sprintf("%v", this.OldVersion) + `,`,
+		`NewVersion:` + fmt.Sprintf("%v", this.NewVersion) + `,`,
+		`Pattern:` + fmt.Sprintf("%v", this.Pattern) + `,`,
+		`Created:` + fmt.Sprintf("%v", this.Created) + `,`,
+		`Matches:` + strings.Replace(fmt.Sprintf("%v", this.Matches), "Match", "Match", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringSearch(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *Search) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSearch
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Search: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Search: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Input", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Input = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Repo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Started", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Started = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Completed", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Completed = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Progress", wireType)
+			}
+			m.Progress = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Progress |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Private", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Private = bool(v != 0)
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= (Search_Status(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Options", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Options == nil {
+				m.Options = &Options{}
+			}
+			if err := m.Options.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matches", wireType)
+			}
+			m.Matches = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Matches |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Revision", wireType)
+			}
+			m.Revision = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Revision |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TokenHash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TokenHash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Repos", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Repos = append(m.Repos, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Revisions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Revisions == nil {
+				m.Revisions = make(map[string]uint32)
+			}
+			var mapkey string
+			var mapvalue uint32
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSearch
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSearch
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSearch
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSearch
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= (uint32(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSearch(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSearch
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Revisions[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WatchID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.WatchID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSearch(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSearch
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Options) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSearch
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Options: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Options: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IgnoreCase", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IgnoreCase = bool(v != 0)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LinesOfContext", wireType)
+			}
+			m.LinesOfContext = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LinesOfContext |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FileRegexp", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FileRegexp = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IgnoreComments", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IgnoreComments = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Mode = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Scope = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Filters", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Filters == nil {
+				m.Filters = &Filters{}
+			}
+			if err := m.Filters.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSearch(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSearch
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Filters) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSearch
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Filters: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Filters: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Slugs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Slugs = append(m.Slugs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Author = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tags = append(m.Tags, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinInstalls", wireType)
+			}
+			m.MinInstalls = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinInstalls |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxInstalls", wireType)
+			}
+			m.MaxInstalls = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxInstalls |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequiresPHP", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RequiresPHP = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TestedMin", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TestedMin = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TestedMax", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TestedMax = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdatedAfter", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UpdatedAfter = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdatedBefore", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UpdatedBefore = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSearch(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSearch
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Summary) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSearch
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Summary: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Summary: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Total", wireType)
+			}
+			m.Total = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Total |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field List", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.List == nil {
+				m.List = make(map[string]*Result)
+			}
+			var mapkey string
+			var mapvalue *Result
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowSearch
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSearch
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthSearch
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowSearch
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= (int(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthSearch
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if mapmsglen < 0 {
+						return ErrInvalidLengthSearch
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &Result{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipSearch(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthSearch
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.List[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSearch(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSearch
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Result) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSearch
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Result: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Result: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Slug", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Slug = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Homepage", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Homepage = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ActiveInstalls", wireType)
+			}
+			m.ActiveInstalls = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ActiveInstalls |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matches", wireType)
+			}
+			m.Matches = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Matches |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Repo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FilesWithMatch", wireType)
+			}
+			m.FilesWithMatch = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FilesWithMatch |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Versions", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Versions = append(m.Versions, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSearch(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSearch
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Matches) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSearch
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Matches: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Matches: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field List", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.List = append(m.List, &Match{})
+			if err := m.List[len(m.List)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Files", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Files = append(m.Files, &FileCount{})
+			if err := m.Files[len(m.Files)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSearch(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSearch
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FileCount) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSearch
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FileCount: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FileCount: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.File = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matches", wireType)
+			}
+			m.Matches = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Matches |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSearch(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSearch
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return nil
 }
-func (m *Search) Unmarshal(dAtA []byte) error {
+func (m *Match) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -946,15 +4007,15 @@ func (m *Search) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Search: wiretype end group for non-group")
+			return fmt.Errorf("proto: Match: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Search: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Match: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Slug", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -979,11 +4040,11 @@ func (m *Search) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ID = string(dAtA[iNdEx:postIndex])
+			m.Slug = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Input", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1008,11 +4069,30 @@ func (m *Search) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Input = string(dAtA[iNdEx:postIndex])
+			m.File = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LineNum", wireType)
+			}
+			m.LineNum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LineNum |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LineText", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1037,11 +4117,11 @@ func (m *Search) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Repo = string(dAtA[iNdEx:postIndex])
+			m.LineText = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 4:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Started", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Before", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1066,11 +4146,11 @@ func (m *Search) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Started = string(dAtA[iNdEx:postIndex])
+			m.Before = append(m.Before, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 5:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Completed", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field After", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1095,13 +4175,13 @@ func (m *Search) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Completed = string(dAtA[iNdEx:postIndex])
+			m.After = append(m.After, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 6:
+		case 7:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Progress", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MatchStart", wireType)
 			}
-			m.Progress = 0
+			m.MatchStart = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1111,16 +4191,16 @@ func (m *Search) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Progress |= (uint32(b) & 0x7F) << shift
+				m.MatchStart |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
+		case 8:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Private", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MatchEnd", wireType)
 			}
-			var v int
+			m.MatchEnd = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1130,17 +4210,16 @@ func (m *Search) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				m.MatchEnd |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Private = bool(v != 0)
-		case 8:
+		case 9:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TextOffset", wireType)
 			}
-			m.Status = 0
+			m.TextOffset = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1150,16 +4229,16 @@ func (m *Search) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Status |= (Search_Status(b) & 0x7F) << shift
+				m.TextOffset |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 9:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Options", wireType)
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Truncated", wireType)
 			}
-			var msglen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1169,30 +4248,17 @@ func (m *Search) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthSearch
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Options == nil {
-				m.Options = &Options{}
-			}
-			if err := m.Options.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 10:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Matches", wireType)
+			m.Truncated = bool(v != 0)
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
 			}
-			m.Matches = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1202,16 +4268,26 @@ func (m *Search) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Matches |= (uint32(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 11:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Revision", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
 			}
-			m.Revision = 0
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Repo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1221,11 +4297,21 @@ func (m *Search) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Revision |= (uint32(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSearch(dAtA[iNdEx:])
@@ -1247,7 +4333,7 @@ func (m *Search) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Options) Unmarshal(dAtA []byte) error {
+func (m *Watch) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1270,17 +4356,75 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Options: wiretype end group for non-group")
+			return fmt.Errorf("proto: Watch: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Options: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Watch: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IgnoreCase", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
 			}
-			var v int
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Input", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1290,17 +4434,26 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.IgnoreCase = bool(v != 0)
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LinesOfContext", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
 			}
-			m.LinesOfContext = 0
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Input = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1310,14 +4463,24 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.LinesOfContext |= (uint32(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Repo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FileRegexp", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Repos", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1342,13 +4505,13 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.FileRegexp = string(dAtA[iNdEx:postIndex])
+			m.Repos = append(m.Repos, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IgnoreComments", wireType)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Options", wireType)
 			}
-			var v int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1358,17 +4521,30 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.IgnoreComments = bool(v != 0)
-		case 5:
+			if msglen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Options == nil {
+				m.Options = &Options{}
+			}
+			if err := m.Options.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Interval", wireType)
 			}
-			m.Offset = 0
+			m.Interval = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1378,16 +4554,16 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Offset |= (uint32(b) & 0x7F) << shift
+				m.Interval |= (int64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Created", wireType)
 			}
-			m.Limit = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1397,66 +4573,26 @@ func (m *Options) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Limit |= (uint32(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipSearch(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSearch
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Summary) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowSearch
-			}
-			if iNdEx >= l {
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Summary: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Summary: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Total", wireType)
+			m.Created = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastRun", wireType)
 			}
-			m.Total = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1466,16 +4602,26 @@ func (m *Summary) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Total |= (uint64(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LastRun = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field List", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LastSearch", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1485,114 +4631,49 @@ func (m *Summary) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSearch
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.List == nil {
-				m.List = make(map[string]*Result)
+			m.LastSearch = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PendingSearch", wireType)
 			}
-			var mapkey string
-			var mapvalue *Result
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowSearch
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
 				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSearch
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= (uint64(b) & 0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthSearch
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var mapmsglen int
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowSearch
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapmsglen |= (int(b) & 0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					if mapmsglen < 0 {
-						return ErrInvalidLengthSearch
-					}
-					postmsgIndex := iNdEx + mapmsglen
-					if mapmsglen < 0 {
-						return ErrInvalidLengthSearch
-					}
-					if postmsgIndex > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = &Result{}
-					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
-						return err
-					}
-					iNdEx = postmsgIndex
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipSearch(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthSearch
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
 				}
 			}
-			m.List[mapkey] = mapvalue
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PendingSearch = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1615,7 +4696,7 @@ func (m *Summary) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Result) Unmarshal(dAtA []byte) error {
+func (m *WatchChanges) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1638,15 +4719,15 @@ func (m *Result) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Result: wiretype end group for non-group")
+			return fmt.Errorf("proto: WatchChanges: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Result: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: WatchChanges: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Slug", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field WatchID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1671,11 +4752,11 @@ func (m *Result) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Slug = string(dAtA[iNdEx:postIndex])
+			m.WatchID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SearchID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1700,11 +4781,11 @@ func (m *Result) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			m.SearchID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PreviousID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1729,11 +4810,11 @@ func (m *Result) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Version = string(dAtA[iNdEx:postIndex])
+			m.PreviousID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Homepage", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Created", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1758,13 +4839,13 @@ func (m *Result) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Homepage = string(dAtA[iNdEx:postIndex])
+			m.Created = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ActiveInstalls", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Added", wireType)
 			}
-			m.ActiveInstalls = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1774,16 +4855,28 @@ func (m *Result) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ActiveInstalls |= (uint32(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Added = append(m.Added, &Match{})
+			if err := m.Added[len(m.Added)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Matches", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Removed", wireType)
 			}
-			m.Matches = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1793,11 +4886,23 @@ func (m *Result) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Matches |= (uint32(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Removed = append(m.Removed, &Match{})
+			if err := m.Removed[len(m.Removed)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSearch(dAtA[iNdEx:])
@@ -1819,7 +4924,7 @@ func (m *Result) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Matches) Unmarshal(dAtA []byte) error {
+func (m *WatchEvent) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1842,17 +4947,17 @@ func (m *Matches) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Matches: wiretype end group for non-group")
+			return fmt.Errorf("proto: WatchEvent: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Matches: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: WatchEvent: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field List", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -1862,74 +4967,80 @@ func (m *Matches) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSearch
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.List = append(m.List, &Match{})
-			if err := m.List[len(m.List)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.ID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipSearch(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WatchID", wireType)
 			}
-			if skippy < 0 {
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthSearch
 			}
-			if (iNdEx + skippy) > l {
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Match) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowSearch
+			m.WatchID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Match: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Match: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Repo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Slug", wireType)
 			}
@@ -1958,9 +5069,9 @@ func (m *Match) Unmarshal(dAtA []byte) error {
 			}
 			m.Slug = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field OldVersion", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1985,13 +5096,13 @@ func (m *Match) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.File = string(dAtA[iNdEx:postIndex])
+			m.OldVersion = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LineNum", wireType)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewVersion", wireType)
 			}
-			m.LineNum = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowSearch
@@ -2001,14 +5112,24 @@ func (m *Match) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.LineNum |= (uint32(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NewVersion = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LineText", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Pattern", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2033,7 +5154,67 @@ func (m *Match) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.LineText = string(dAtA[iNdEx:postIndex])
+			m.Pattern = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Created", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Created = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matches", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSearch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSearch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Matches = append(m.Matches, &Match{})
+			if err := m.Matches[len(m.Matches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2161,55 +5342,103 @@ var (
 	ErrIntOverflowSearch   = fmt.Errorf("proto: integer overflow")
 )
 
-func init() { proto.RegisterFile("search.proto", fileDescriptor_search_cba70e344642d923) }
-
-var fileDescriptor_search_cba70e344642d923 = []byte{
-	// 738 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0x4d, 0x6f, 0xeb, 0x44,
-	0x14, 0xf5, 0xe4, 0xc3, 0x4e, 0x6e, 0x48, 0x1a, 0x0d, 0xf0, 0xe4, 0x57, 0xd0, 0x24, 0x44, 0x20,
-	0x82, 0x44, 0xf3, 0x50, 0xd8, 0x20, 0xc4, 0x02, 0xa5, 0x20, 0xf4, 0x24, 0xe0, 0x89, 0x09, 0xfb,
-	0xc8, 0x4d, 0x26, 0xce, 0x08, 0xdb, 0x63, 0x79, 0xc6, 0x51, 0xbb, 0x63, 0xc7, 0x96, 0x1d, 0x7f,
-	0x81, 0x9f, 0xc0, 0x4f, 0x78, 0x12, 0x2c, 0xba, 0xec, 0xaa, 0x6a, 0xdc, 0x0d, 0xea, 0xaa, 0x3f,
-	0x01, 0xcd, 0x87, 0x03, 0x42, 0xea, 0x2a, 0xf7, 0x9c, 0x33, 0x9e, 0xb9, 0xf7, 0xdc, 0xa3, 0xc0,
-	0x1b, 0x92, 0x45, 0xc5, 0x7a, 0x37, 0xcb, 0x0b, 0xa1, 0x04, 0xf6, 0x2d, 0x3a, 0x3d, 0x8b, 0xb9,
-	0xda, 0x95, 0x17, 0xb3, 0xb5, 0x48, 0x5f, 0xc4, 0x22, 0x16, 0x2f, 0x8c, 0x7c, 0x51, 0x6e, 0x0d,
-	0x32, 0xc0, 0x54, 0xf6, 0xb3, 0xc9, 0x2f, 0x4d, 0xf0, 0x97, 0xe6, 0x4b, 0xfc, 0x0c, 0x1a, 0x7c,
-	0x13, 0xa2, 0x31, 0x9a, 0x76, 0x17, 0x7e, 0x75, 0x3b, 0x6a, 0xbc, 0xfc, 0x8a, 0x36, 0xf8, 0x06,
-	0xbf, 0x05, 0x6d, 0x9e, 0xe5, 0xa5, 0x0a, 0x1b, 0x5a, 0xa2, 0x16, 0x60, 0x0c, 0xad, 0x82, 0xe5,
-	0x22, 0x6c, 0x1a, 0xd2, 0xd4, 0x38, 0x84, 0x40, 0xaa, 0xa8, 0x50, 0x6c, 0x13, 0xb6, 0x0c, 0x5d,
-	0x43, 0xfc, 0x2e, 0x74, 0xd7, 0x22, 0xcd, 0x13, 0xa6, 0xb5, 0xb6, 0xd1, 0xfe, 0x25, 0xf0, 0x29,
-	0x74, 0xf2, 0x42, 0xc4, 0x05, 0x93, 0x32, 0xf4, 0xc7, 0x68, 0xda, 0xa7, 0x47, 0xac, 0xef, 0xcc,
-	0x0b, 0xbe, 0x8f, 0x14, 0x0b, 0x83, 0x31, 0x9a, 0x76, 0x68, 0x0d, 0xf1, 0x19, 0xf8, 0x52, 0x45,
-	0xaa, 0x94, 0x61, 0x67, 0x8c, 0xa6, 0x83, 0xf9, 0xdb, 0x33, 0x67, 0xc8, 0xd2, 0xfd, 0x18, 0x91,
-	0xba, 0x43, 0xf8, 0x23, 0x08, 0x44, 0xae, 0xb8, 0xc8, 0x64, 0xd8, 0x1d, 0xa3, 0x69, 0x6f, 0x7e,
-	0x52, 0x9f, 0x7f, 0x65, 0x69, 0x5a, 0xeb, 0xf8, 0x03, 0x08, 0xd2, 0x48, 0xad, 0x77, 0x4c, 0x86,
-	0xa0, 0xdb, 0x59, 0xf4, 0x1e, 0x6e, 0x47, 0x35, 0x45, 0xeb, 0x42, 0xb7, 0x5d, 0xb0, 0x3d, 0x97,
-	0x5c, 0x64, 0x61, 0xcf, 0xb6, 0x5d, 0xe3, 0xc9, 0x27, 0xe0, 0xdb, 0xf7, 0x31, 0x80, 0xff, 0x43,
-	0xc9, 0x4a, 0xb6, 0x19, 0x7a, 0xb8, 0x07, 0xc1, 0xd2, 0x3a, 0x32, 0x44, 0xb8, 0x0f, 0xdd, 0xf3,
-	0xda, 0x82, 0x61, 0x63, 0xf2, 0x17, 0x82, 0xc0, 0x75, 0x82, 0x47, 0xd0, 0xe3, 0x71, 0x26, 0x0a,
-	0xb6, 0x5a, 0x47, 0x92, 0x99, 0x9d, 0x74, 0x28, 0x58, 0xea, 0x3c, 0x92, 0x0c, 0x4f, 0x61, 0x98,
-	0xf0, 0x8c, 0xc9, 0x95, 0xd8, 0xae, 0xd6, 0x22, 0x53, 0xec, 0xd2, 0xae, 0xa7, 0x4f, 0x07, 0x86,
-	0x7f, 0xb5, 0x3d, 0xb7, 0xac, 0xbe, 0x6a, 0xcb, 0x13, 0xb6, 0x2a, 0x58, 0xcc, 0x2e, 0x73, 0xb7,
-	0x2e, 0xd0, 0x14, 0x35, 0x0c, 0xfe, 0x10, 0x4e, 0xea, 0xb7, 0x44, 0x9a, 0xb2, 0x4c, 0x49, 0xb3,
-	0xbc, 0x0e, 0x1d, 0xb8, 0xf7, 0x1c, 0x8b, 0x9f, 0x81, 0x2f, 0xb6, 0x5b, 0xc9, 0x94, 0x59, 0x60,
-	0x9f, 0x3a, 0xa4, 0xf3, 0x91, 0xf0, 0x94, 0x2b, 0xb7, 0x3a, 0x0b, 0x26, 0xbf, 0x21, 0x08, 0x96,
-	0x65, 0x9a, 0x46, 0xc5, 0x95, 0x3e, 0xa1, 0x84, 0x8a, 0x12, 0x33, 0x48, 0x8b, 0x5a, 0x80, 0xcf,
-	0xa0, 0x95, 0x70, 0xa9, 0xfb, 0x6e, 0x4e, 0x7b, 0xf3, 0xe7, 0xc7, 0xed, 0xd9, 0x8f, 0x66, 0xdf,
-	0x72, 0xa9, 0xbe, 0xce, 0x54, 0x71, 0x45, 0xcd, 0xb1, 0xd3, 0x6f, 0xa0, 0x7b, 0xa4, 0xf0, 0x10,
-	0x9a, 0x3f, 0xb1, 0x2b, 0x1b, 0x56, 0xaa, 0x4b, 0xfc, 0x3e, 0xb4, 0xf7, 0x51, 0x52, 0x32, 0x63,
-	0x43, 0x6f, 0x3e, 0xa8, 0xaf, 0xa3, 0x4c, 0x96, 0x89, 0xa2, 0x56, 0xfc, 0xbc, 0xf1, 0x19, 0x9a,
-	0xfc, 0x89, 0xc0, 0xb7, 0xac, 0x0e, 0xb1, 0x4c, 0xca, 0xd8, 0xdd, 0x63, 0x6a, 0xcd, 0x65, 0x51,
-	0xca, 0x5c, 0xda, 0x4d, 0xad, 0x43, 0xb8, 0x67, 0x85, 0x59, 0xb4, 0x35, 0xb0, 0x86, 0x3a, 0x03,
-	0x3b, 0x91, 0xb2, 0x3c, 0x8a, 0x99, 0xcb, 0xfc, 0x11, 0xe3, 0x2f, 0xe0, 0x24, 0x5a, 0x2b, 0xbe,
-	0x67, 0x2b, 0x9e, 0x49, 0x15, 0x25, 0x89, 0xb4, 0xce, 0x2d, 0xde, 0x7c, 0xb8, 0x1d, 0xfd, 0x5f,
-	0xa2, 0x03, 0x4b, 0xbc, 0x74, 0xf8, 0xbf, 0x21, 0xf4, 0x9f, 0x0e, 0xe1, 0xe4, 0x63, 0x08, 0xbe,
-	0x73, 0x79, 0x7c, 0xcf, 0x19, 0x8a, 0x8c, 0xa1, 0xfd, 0xda, 0x01, 0x23, 0x5b, 0x13, 0x27, 0x31,
-	0xb4, 0x0d, 0x7c, 0x6a, 0x72, 0x9d, 0x8b, 0x7a, 0x72, 0x5d, 0xe3, 0xe7, 0xd0, 0xd1, 0x81, 0x5a,
-	0x65, 0x65, 0x6a, 0x46, 0xef, 0xd3, 0x40, 0xe3, 0xef, 0xcb, 0x14, 0xbf, 0x03, 0x5d, 0x23, 0x99,
-	0xf0, 0xb9, 0xd9, 0x35, 0xf1, 0x23, 0xbb, 0x54, 0x8b, 0x2f, 0x5f, 0x1f, 0x88, 0x77, 0x7d, 0x20,
-	0xde, 0xcd, 0x81, 0x78, 0x77, 0x07, 0xe2, 0x3d, 0x1e, 0x88, 0xf7, 0x73, 0x45, 0xd0, 0xef, 0x15,
-	0xf1, 0xfe, 0xa8, 0x08, 0x7a, 0x5d, 0x11, 0x74, 0x5d, 0x11, 0x74, 0x57, 0x11, 0xf4, 0x77, 0x45,
-	0xbc, 0xc7, 0x8a, 0xa0, 0x5f, 0xef, 0x89, 0x77, 0x7d, 0x4f, 0xbc, 0x9b, 0x7b, 0xe2, 0x5d, 0xf8,
-	0xe6, 0x0f, 0xea, 0xd3, 0x7f, 0x02, 0x00, 0x00, 0xff, 0xff, 0x99, 0x85, 0x6f, 0x01, 0xe7, 0x04,
-	0x00, 0x00,
+func init() { proto.RegisterFile("search.proto", fileDescriptor_search_627ba492b0d2b684) }
+
+var fileDescriptor_search_627ba492b0d2b684 = []byte{
+	// 1515 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x57, 0xcd, 0x6e, 0x1b, 0xc9,
+	0x11, 0xe6, 0xf0, 0x67, 0xc8, 0x29, 0xfe, 0x88, 0xe9, 0x38, 0xc6, 0x58, 0x89, 0x49, 0x99, 0xb6,
+	0x23, 0x39, 0x80, 0xe4, 0x40, 0xb9, 0x18, 0x89, 0x11, 0x20, 0x94, 0xec, 0x98, 0x40, 0x1c, 0x29,
+	0x2d, 0x27, 0x46, 0x4e, 0x93, 0x16, 0xa7, 0x49, 0x0e, 0x3c, 0x7f, 0x99, 0xe9, 0xa1, 0xe4, 0x5b,
+	0xde, 0x20, 0x01, 0x72, 0xc8, 0x2b, 0xe4, 0x0d, 0x12, 0xc0, 0x2f, 0xe0, 0xdb, 0xfa, 0xb8, 0x27,
+	0xc2, 0xe2, 0x5e, 0x16, 0x3a, 0xf9, 0xbc, 0xa7, 0x45, 0x57, 0xf7, 0x50, 0x23, 0x5b, 0xd2, 0x9e,
+	0xd8, 0xdf, 0x57, 0x35, 0xfd, 0xf3, 0x55, 0x55, 0x77, 0x11, 0x5a, 0x29, 0x67, 0xc9, 0x78, 0xb6,
+	0x13, 0x27, 0x91, 0x88, 0x88, 0xa9, 0xd0, 0xfa, 0xf6, 0xd4, 0x13, 0xb3, 0xec, 0x78, 0x67, 0x1c,
+	0x05, 0x8f, 0xa7, 0xd1, 0x34, 0x7a, 0x8c, 0xe6, 0xe3, 0x6c, 0x82, 0x08, 0x01, 0x8e, 0xd4, 0x67,
+	0x83, 0x7f, 0xd6, 0xc0, 0x3c, 0xc2, 0x2f, 0xc9, 0x6d, 0x28, 0x7b, 0xae, 0x6d, 0x6c, 0x18, 0x5b,
+	0xd6, 0xd0, 0x5c, 0x2e, 0xfa, 0xe5, 0xd1, 0x3e, 0x2d, 0x7b, 0x2e, 0xb9, 0x05, 0x35, 0x2f, 0x8c,
+	0x33, 0x61, 0x97, 0xa5, 0x89, 0x2a, 0x40, 0x08, 0x54, 0x13, 0x1e, 0x47, 0x76, 0x05, 0x49, 0x1c,
+	0x13, 0x1b, 0xea, 0xa9, 0x60, 0x89, 0xe0, 0xae, 0x5d, 0x45, 0x3a, 0x87, 0xe4, 0x67, 0x60, 0x8d,
+	0xa3, 0x20, 0xf6, 0xb9, 0xb4, 0xd5, 0xd0, 0x76, 0x41, 0x90, 0x75, 0x68, 0xc4, 0x49, 0x34, 0x4d,
+	0x78, 0x9a, 0xda, 0xe6, 0x86, 0xb1, 0xd5, 0xa6, 0x2b, 0x2c, 0xe7, 0x8c, 0x13, 0x6f, 0xce, 0x04,
+	0xb7, 0xeb, 0x1b, 0xc6, 0x56, 0x83, 0xe6, 0x90, 0x6c, 0x83, 0x99, 0x0a, 0x26, 0xb2, 0xd4, 0x6e,
+	0x6c, 0x18, 0x5b, 0x9d, 0xdd, 0x9f, 0xec, 0x68, 0x41, 0x8e, 0xf4, 0x0f, 0x1a, 0xa9, 0x76, 0x22,
+	0x8f, 0xa0, 0x1e, 0xc5, 0xc2, 0x8b, 0xc2, 0xd4, 0xb6, 0x36, 0x8c, 0xad, 0xe6, 0xee, 0x5a, 0xee,
+	0x7f, 0xa0, 0x68, 0x9a, 0xdb, 0xc9, 0x43, 0xa8, 0x07, 0x4c, 0x8c, 0x67, 0x3c, 0xb5, 0x41, 0x6e,
+	0x67, 0xd8, 0x3c, 0x5f, 0xf4, 0x73, 0x8a, 0xe6, 0x03, 0xb9, 0xed, 0x84, 0xcf, 0xbd, 0xd4, 0x8b,
+	0x42, 0xbb, 0xa9, 0xb6, 0x9d, 0x63, 0xf2, 0x00, 0x40, 0x44, 0x6f, 0x78, 0xe8, 0xcc, 0x58, 0x3a,
+	0xb3, 0x5b, 0x28, 0x6a, 0xed, 0x7c, 0xd1, 0x37, 0xb6, 0xa9, 0x85, 0x86, 0x17, 0x2c, 0x9d, 0x49,
+	0x69, 0xa5, 0x70, 0xa9, 0xdd, 0xde, 0xa8, 0x48, 0x69, 0x11, 0x90, 0xdf, 0x80, 0x95, 0xcf, 0x93,
+	0xda, 0x9d, 0x8d, 0xca, 0x56, 0x73, 0xf7, 0xee, 0x67, 0x67, 0xa3, 0xb9, 0xfd, 0x59, 0x28, 0x92,
+	0xb7, 0xf4, 0xc2, 0x9f, 0xfc, 0x1c, 0x1a, 0x27, 0x72, 0x7f, 0x8e, 0xe7, 0xda, 0x6b, 0xb8, 0x6c,
+	0x73, 0xb9, 0xe8, 0xd7, 0x5f, 0x4b, 0x6e, 0xb4, 0x4f, 0xeb, 0x68, 0x1c, 0xb9, 0xeb, 0x4f, 0xa1,
+	0x73, 0x79, 0x12, 0xd2, 0x85, 0xca, 0x1b, 0xfe, 0x56, 0x25, 0x00, 0x95, 0x43, 0xb9, 0xbd, 0x39,
+	0xf3, 0x33, 0x8e, 0x91, 0x6f, 0x53, 0x05, 0x7e, 0x5d, 0x7e, 0x62, 0x0c, 0xfe, 0x0a, 0xa6, 0x92,
+	0x97, 0x00, 0x98, 0x7f, 0xca, 0x78, 0xc6, 0xdd, 0x6e, 0x89, 0x34, 0xa1, 0x7e, 0xa4, 0x02, 0xde,
+	0x35, 0x48, 0x1b, 0xac, 0xbd, 0x3c, 0xc2, 0xdd, 0x32, 0x42, 0x16, 0x8e, 0xb9, 0xef, 0x73, 0xb7,
+	0x5b, 0x21, 0x2d, 0x68, 0xbc, 0xf2, 0x02, 0xee, 0x1e, 0x64, 0xa2, 0x5b, 0x95, 0x93, 0x3c, 0x67,
+	0x9e, 0xb4, 0xd4, 0x06, 0xef, 0xca, 0x50, 0xd7, 0x11, 0x21, 0x7d, 0x68, 0x7a, 0xd3, 0x30, 0x4a,
+	0xb8, 0x33, 0x66, 0x29, 0xc7, 0xad, 0x35, 0x28, 0x28, 0x6a, 0x8f, 0xa5, 0x9c, 0x6c, 0x41, 0xd7,
+	0xf7, 0x42, 0x9e, 0x3a, 0xd1, 0xc4, 0x19, 0x47, 0xa1, 0xe0, 0xa7, 0x42, 0x6f, 0xb6, 0x83, 0xfc,
+	0xc1, 0x64, 0x4f, 0xb1, 0x72, 0xaa, 0x89, 0xe7, 0x73, 0x27, 0xe1, 0x53, 0x7e, 0x1a, 0xeb, 0xb4,
+	0x05, 0x49, 0x51, 0x64, 0xc8, 0x26, 0xac, 0xe5, 0x6b, 0x45, 0x41, 0xc0, 0x43, 0x91, 0x62, 0x12,
+	0x37, 0x68, 0x47, 0xaf, 0xa7, 0x59, 0x72, 0x1b, 0xcc, 0x68, 0x32, 0x49, 0xb9, 0xc0, 0x44, 0x6e,
+	0x53, 0x8d, 0xa4, 0x5a, 0xbe, 0x17, 0x78, 0x42, 0xa7, 0xb0, 0x02, 0xb2, 0x4e, 0x82, 0xc8, 0x55,
+	0xc9, 0x6b, 0x51, 0x1c, 0x4b, 0xcf, 0x74, 0x1c, 0xc5, 0x1c, 0x13, 0xd7, 0xa2, 0x0a, 0xc8, 0x04,
+	0x9d, 0x78, 0xbe, 0xe0, 0xc9, 0x17, 0x09, 0xfa, 0x5c, 0xd1, 0x34, 0xb7, 0xcb, 0xa2, 0x98, 0xf3,
+	0x04, 0x13, 0x0f, 0x54, 0xa1, 0x69, 0x38, 0xf8, 0xaa, 0x0c, 0x75, 0xed, 0x8e, 0xcb, 0xf8, 0xd9,
+	0x34, 0xb5, 0x0d, 0x95, 0x5d, 0x08, 0xe4, 0xf6, 0x59, 0x26, 0x66, 0x51, 0xa2, 0xeb, 0x59, 0x23,
+	0xb9, 0x51, 0xc1, 0xa6, 0xa9, 0x5d, 0x41, 0x67, 0x1c, 0x93, 0x7b, 0xd0, 0x0a, 0xbc, 0xd0, 0xf1,
+	0xc2, 0x54, 0x30, 0xdf, 0x57, 0x82, 0xb4, 0x69, 0x33, 0xf0, 0xc2, 0x91, 0xa6, 0xd0, 0x85, 0x9d,
+	0x5e, 0xb8, 0xd4, 0xb4, 0x0b, 0x3b, 0x5d, 0xb9, 0xec, 0x42, 0x2b, 0xe1, 0x7f, 0xcf, 0xbc, 0x84,
+	0xa7, 0x4e, 0x3c, 0x8b, 0x51, 0x1f, 0x6b, 0xb8, 0xb6, 0x5c, 0xf4, 0x9b, 0x54, 0xf3, 0x87, 0x2f,
+	0x0e, 0x69, 0x33, 0x77, 0x3a, 0x9c, 0xc5, 0xe4, 0x2e, 0x80, 0xe0, 0xa9, 0xe0, 0xae, 0x13, 0x78,
+	0xa1, 0x16, 0xcf, 0x52, 0xcc, 0x4b, 0x2f, 0x2c, 0x9a, 0xd9, 0xa9, 0xdd, 0xb8, 0x64, 0x66, 0xa7,
+	0xe4, 0x3e, 0xb4, 0xb3, 0xd8, 0x65, 0xd2, 0xce, 0x26, 0x82, 0x27, 0x28, 0xa8, 0x45, 0x5b, 0x9a,
+	0xfc, 0x9d, 0xe4, 0xc8, 0x43, 0xe8, 0xe4, 0x4e, 0xc7, 0x7c, 0x12, 0x25, 0x5c, 0x6b, 0x99, 0x7f,
+	0x3a, 0x44, 0x72, 0xf0, 0x1f, 0x03, 0xea, 0x47, 0x59, 0x10, 0xb0, 0x04, 0x0b, 0x42, 0x44, 0x82,
+	0xf9, 0x98, 0x89, 0x55, 0xaa, 0x00, 0xd9, 0x86, 0xaa, 0xef, 0xa5, 0x32, 0xf1, 0x64, 0xa9, 0xde,
+	0x59, 0x95, 0xaa, 0xfa, 0x68, 0xe7, 0x0f, 0x5e, 0x2a, 0x54, 0x99, 0xa2, 0xdb, 0xfa, 0xef, 0xc1,
+	0x5a, 0x51, 0x57, 0x14, 0xdd, 0x83, 0x62, 0xd1, 0x35, 0x77, 0x3b, 0xf9, 0x74, 0x94, 0xa7, 0x99,
+	0x2f, 0x8a, 0x45, 0xf8, 0xae, 0x0c, 0xa6, 0x62, 0x65, 0xf0, 0x64, 0x74, 0xf5, 0x3c, 0x38, 0x96,
+	0x5c, 0xc8, 0x02, 0xae, 0xc3, 0x8c, 0xe3, 0x62, 0xe2, 0x54, 0x2e, 0x25, 0x8e, 0xbc, 0xcc, 0x66,
+	0x51, 0xc0, 0x63, 0x36, 0xe5, 0xfa, 0xf2, 0x5e, 0x61, 0xf2, 0x14, 0xd6, 0xd8, 0x58, 0x78, 0x73,
+	0xfe, 0x59, 0x98, 0x87, 0x3f, 0x3e, 0x5f, 0xf4, 0x3f, 0x37, 0xd1, 0x8e, 0x22, 0x56, 0xe1, 0x2f,
+	0xdc, 0xa6, 0xe6, 0x0d, 0xb7, 0x69, 0xfe, 0xa0, 0xd4, 0x0b, 0x0f, 0xca, 0x6f, 0xa1, 0x2b, 0x2b,
+	0x34, 0x75, 0x4e, 0x3c, 0x31, 0x73, 0xd0, 0x13, 0x83, 0xdd, 0x1e, 0xde, 0x3a, 0x5f, 0xf4, 0xbf,
+	0xb0, 0xd1, 0x0e, 0x32, 0xaf, 0x3d, 0x31, 0x7b, 0x29, 0xb1, 0x3c, 0x94, 0x3e, 0x9f, 0xac, 0x29,
+	0x99, 0xd7, 0x2b, 0x3c, 0xf8, 0x33, 0xd4, 0x5f, 0xea, 0xa5, 0xef, 0xe9, 0x00, 0x1a, 0x18, 0xc0,
+	0x76, 0xae, 0x38, 0x9a, 0x55, 0xd0, 0xc8, 0x26, 0xd4, 0x70, 0x6e, 0x1d, 0xe4, 0x1f, 0x15, 0x4a,
+	0x93, 0xef, 0x45, 0x59, 0x28, 0xa8, 0xb2, 0x0f, 0xfe, 0x06, 0xd6, 0x8a, 0x93, 0x67, 0x92, 0x6c,
+	0x1e, 0x16, 0x39, 0x2e, 0xca, 0x51, 0xbe, 0x41, 0x8e, 0x6b, 0x23, 0x35, 0xf8, 0x58, 0x86, 0x9a,
+	0x3a, 0xde, 0x35, 0x51, 0xc7, 0x25, 0xcb, 0x85, 0x25, 0xef, 0x40, 0x43, 0xde, 0x86, 0x4e, 0x98,
+	0x05, 0x38, 0x59, 0x9b, 0xd6, 0x25, 0xfe, 0x63, 0x16, 0x90, 0x9f, 0x82, 0x85, 0x26, 0xbc, 0x39,
+	0x75, 0xdc, 0x25, 0xf1, 0x4a, 0xde, 0x99, 0xb7, 0xc1, 0xd4, 0x95, 0x51, 0x43, 0xf1, 0x34, 0x92,
+	0x65, 0xa0, 0xca, 0xca, 0x44, 0x5a, 0x01, 0xf2, 0x4b, 0x68, 0xe2, 0xe6, 0x1d, 0x7c, 0xf4, 0x31,
+	0x8e, 0xed, 0xe1, 0xda, 0xf9, 0xa2, 0x5f, 0xa4, 0x29, 0x20, 0xc0, 0x67, 0x82, 0xfc, 0x02, 0x2c,
+	0x65, 0xe2, 0xa1, 0xab, 0xe3, 0xda, 0x3e, 0x5f, 0xf4, 0x2f, 0x48, 0xda, 0xc0, 0xe1, 0xb3, 0xd0,
+	0x95, 0xf7, 0xb7, 0xdc, 0xa3, 0xa3, 0xaf, 0x5e, 0x0b, 0x8f, 0x01, 0x92, 0x3a, 0x40, 0x46, 0xb6,
+	0x18, 0x22, 0xc9, 0xc2, 0xb1, 0x2c, 0x5d, 0xac, 0xe4, 0x06, 0xbd, 0x20, 0x56, 0xd9, 0xd5, 0xbc,
+	0xdc, 0xae, 0xe4, 0x12, 0xb7, 0x2e, 0x4b, 0xfc, 0xbf, 0x32, 0xd4, 0xf0, 0xc5, 0xbc, 0xb6, 0x29,
+	0xba, 0xaa, 0xb8, 0x56, 0x8d, 0x52, 0xe5, 0xaa, 0x46, 0xa9, 0x5a, 0x58, 0x79, 0xf5, 0xee, 0xd7,
+	0x8a, 0xef, 0x7e, 0xa1, 0x43, 0x31, 0x7f, 0xa0, 0x43, 0x59, 0x87, 0x86, 0x17, 0x0a, 0x9e, 0xcc,
+	0x99, 0x8f, 0x42, 0x57, 0xe8, 0x0a, 0xcb, 0x63, 0x8d, 0x13, 0x8e, 0x32, 0xa8, 0x8b, 0x31, 0x87,
+	0x98, 0x07, 0x2c, 0x15, 0x4e, 0x92, 0x85, 0xfa, 0x46, 0xac, 0x4b, 0x4c, 0xb3, 0x50, 0xca, 0x8b,
+	0x26, 0xb5, 0xa0, 0xbe, 0x09, 0x41, 0x52, 0xba, 0x3b, 0x7c, 0x08, 0x9d, 0x98, 0x87, 0xae, 0x17,
+	0x4e, 0x73, 0x1f, 0x25, 0x65, 0x5b, 0xb3, 0xca, 0x6d, 0xf0, 0x9d, 0x01, 0x2d, 0x54, 0x6e, 0x6f,
+	0xc6, 0xc2, 0x29, 0xbf, 0xdc, 0x8f, 0x18, 0xd7, 0xf7, 0x23, 0xe4, 0x11, 0x58, 0x6a, 0x5e, 0xe9,
+	0x88, 0xaa, 0x0e, 0x5b, 0xcb, 0x45, 0xbf, 0xa1, 0xe6, 0x1d, 0xed, 0xd3, 0x86, 0x32, 0x8f, 0x5c,
+	0xf2, 0x18, 0x9a, 0xb1, 0x6c, 0x78, 0xa2, 0x2c, 0x95, 0xce, 0xa8, 0xf6, 0xb0, 0xb3, 0x5c, 0xf4,
+	0xe1, 0x50, 0xd3, 0xa3, 0x7d, 0x0a, 0xb9, 0xcb, 0xc8, 0x2d, 0x2a, 0x52, 0xbd, 0xac, 0xc8, 0x7d,
+	0xa8, 0x31, 0xd7, 0xc5, 0x9e, 0xf4, 0x8a, 0xd2, 0x57, 0x36, 0xb2, 0x09, 0xf5, 0x84, 0x07, 0xd1,
+	0x9c, 0xbb, 0xb6, 0x79, 0x95, 0x5b, 0x6e, 0x1d, 0xfc, 0xbb, 0x0c, 0x80, 0x07, 0x7b, 0x36, 0xe7,
+	0xa1, 0xb8, 0x36, 0x77, 0x8a, 0x92, 0x94, 0x6f, 0x90, 0xe4, 0xaa, 0x16, 0x3b, 0x2f, 0xf9, 0x6a,
+	0xa1, 0xe4, 0xfb, 0xd0, 0x8c, 0x7c, 0xd7, 0xc9, 0x73, 0x59, 0xb5, 0xd7, 0x10, 0xf9, 0xee, 0x5f,
+	0x14, 0x23, 0x1d, 0x42, 0x7e, 0xb2, 0x72, 0x30, 0x95, 0x43, 0xc8, 0x4f, 0x72, 0x07, 0xd9, 0x64,
+	0x33, 0x21, 0x78, 0x92, 0x3f, 0xb5, 0x39, 0xbc, 0x21, 0x99, 0x36, 0x2f, 0xee, 0x31, 0xeb, 0x4a,
+	0x55, 0xb4, 0x75, 0xf8, 0xe4, 0xfd, 0x59, 0xaf, 0xf4, 0xe1, 0xac, 0x57, 0xfa, 0xfa, 0xac, 0x57,
+	0xfa, 0x78, 0xd6, 0x2b, 0x7d, 0x3a, 0xeb, 0x95, 0xfe, 0xb1, 0xec, 0x19, 0xff, 0x5d, 0xf6, 0x4a,
+	0xff, 0x5f, 0xf6, 0x8c, 0xf7, 0xcb, 0x9e, 0xf1, 0x61, 0xd9, 0x33, 0x3e, 0x2e, 0x7b, 0xc6, 0xb7,
+	0xcb, 0x5e, 0xe9, 0xd3, 0xb2, 0x67, 0xfc, 0xeb, 0x9b, 0x5e, 0xe9, 0xd8, 0xc4, 0xff, 0x28, 0xbf,
+	0xfa, 0x7e, 0x00, 0xe8, 0x7c, 0xad, 0x45, 0xea, 0x0c, 0x00, 0x00,
 }
@@ -0,0 +1,135 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wpdirectory/wpdir/internal/index"
+	"github.com/wpdirectory/wpdir/internal/metrics"
+	"github.com/wpdirectory/wpdir/internal/repo"
+)
+
+func init() {
+	metrics.Setup()
+}
+
+func TestSearchRepos(t *testing.T) {
+	tests := []struct {
+		srch  Search
+		names []string
+		key   string
+	}{
+		{Search{Repo: "plugins"}, []string{"plugins"}, "akismet"},
+		{Search{Repo: "themes", Repos: []string{"themes"}}, []string{"themes"}, "akismet"},
+		{Search{Repo: "plugins,themes", Repos: []string{"plugins", "themes"}}, []string{"plugins", "themes"}, "themes/akismet"},
+	}
+
+	for _, test := range tests {
+		names := test.srch.RepoNames()
+		if !reflect.DeepEqual(names, test.names) {
+			t.Errorf("Expected %+v got %+v", test.names, names)
+		}
+		key := test.srch.ResultKey(names[len(names)-1], "akismet")
+		if key != test.key {
+			t.Errorf("Expected %s got %s", test.key, key)
+		}
+	}
+}
+
+func TestManagerRepos(t *testing.T) {
+	sm := NewManager(1, 1, 0, 0)
+
+	plugins := &repo.Repo{Name: "plugins", ExtType: "plugins"}
+	themes := &repo.Repo{Name: "themes", ExtType: "themes"}
+	internal := &repo.Repo{Name: "internal", ExtType: "plugins"}
+	replaced := &repo.Repo{Name: "plugins", ExtType: "plugins"}
+	for _, r := range []*repo.Repo{plugins, themes, internal, replaced} {
+		sm.AddRepo(r)
+	}
+
+	want := []string{"plugins", "themes", "internal"}
+	if names := sm.RepoNames(); !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected %+v got %+v", want, names)
+	}
+
+	repos := sm.Repos()
+	wantRepos := []*repo.Repo{replaced, themes, internal}
+	if len(repos) != len(wantRepos) {
+		t.Fatalf("Expected %d repos got %d", len(wantRepos), len(repos))
+	}
+	for i := range repos {
+		if repos[i] != wantRepos[i] {
+			t.Errorf("Expected %s at %d got %s", wantRepos[i].Name, i, repos[i].Name)
+		}
+	}
+
+	tests := []struct {
+		name string
+		repo *repo.Repo
+	}{
+		{"plugins", replaced},
+		{"internal", internal},
+		{"missing", nil},
+	}
+	for _, test := range tests {
+		if r := sm.Repo(test.name); r != test.repo {
+			t.Errorf("Expected %+v got %+v for %s", test.repo, r, test.name)
+		}
+	}
+
+	// Changing the returned names does not change the Manager
+	names := sm.RepoNames()
+	names[0] = "changed"
+	if sm.RepoNames()[0] != "plugins" {
+		t.Errorf("Expected RepoNames to return a copy")
+	}
+}
+
+func TestIndexOptions(t *testing.T) {
+	tests := []struct {
+		opts *Options
+		want *index.SearchOptions
+	}{
+		{nil, &index.SearchOptions{}},
+		{&Options{}, &index.SearchOptions{}},
+		{
+			&Options{IgnoreCase: true, LinesOfContext: 10, FileRegexp: `\.php$`, IgnoreComments: true, Offset: 20, Limit: 5, Mode: index.ModeWord, Scope: index.ScopeFile, Version: "all"},
+			&index.SearchOptions{IgnoreCase: true, LinesOfContext: 10, FileRegexp: `\.php$`, IgnoreComments: true, Offset: 20, Limit: 5, Mode: index.ModeWord, Scope: index.ScopeFile},
+		},
+	}
+
+	for _, test := range tests {
+		if got := indexOptions(test.opts); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Expected %+v got %+v for %+v", test.want, got, test.opts)
+		}
+	}
+}
+
+func TestNewSearchOptions(t *testing.T) {
+	sm := NewManager(1, 1, 0, 0)
+
+	opts := Options{IgnoreCase: true, LinesOfContext: 0, FileRegexp: `^inc/`, Offset: 100, Limit: 50, Mode: index.ModeLiteral}
+	ID, err := sm.NewSearch(Request{Input: "eval(", Repo: "plugins", Opts: opts})
+	if err != nil {
+		t.Fatalf("Could not create search: %s", err)
+	}
+
+	// Searches are stored as protobuf, the Options must survive the trip
+	srch := sm.Get(ID)
+	b, err := srch.Marshal()
+	if err != nil {
+		t.Fatalf("Could not marshal search: %s", err)
+	}
+	var stored Search
+	if err = stored.Unmarshal(b); err != nil {
+		t.Fatalf("Could not unmarshal search: %s", err)
+	}
+	if stored.Options == nil || !reflect.DeepEqual(*stored.Options, opts) {
+		t.Errorf("Expected %+v got %+v", opts, stored.Options)
+	}
+
+	want := &index.SearchOptions{IgnoreCase: true, FileRegexp: `^inc/`, Offset: 100, Limit: 50, Mode: index.ModeLiteral}
+	if got := indexOptions(stored.Options); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v got %+v", want, got)
+	}
+}
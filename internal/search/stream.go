@@ -0,0 +1,193 @@
+package search
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wpdirectory/wpdir/internal/db"
+)
+
+const (
+	// EventResult is sent when an Extension has finished being searched
+	EventResult = "result"
+	// EventCompleted is sent once the Search has finished
+	EventCompleted = "completed"
+
+	// streamRetention is how long a finished Stream is kept in memory
+	// so clients can resume from where they left off
+	streamRetention = 2 * time.Minute
+)
+
+// Event contains an update sent to clients watching a Search
+type Event struct {
+	Type    string        `json:"type"`
+	Result  *Result       `json:"result,omitempty"`
+	Matches *Matches      `json:"matches,omitempty"`
+	Status  Search_Status `json:"status"`
+	Total   uint32        `json:"total"`
+}
+
+// Stream holds the Events published by a Search
+// Events are kept so that late subscribers receive the full history
+type Stream struct {
+	events []*Event
+	notify chan struct{}
+	done   bool
+	sync.Mutex
+}
+
+// newStream returns an empty Stream
+func newStream() *Stream {
+	return &Stream{
+		notify: make(chan struct{}),
+	}
+}
+
+// publish adds an Event and wakes any waiting subscribers
+func (st *Stream) publish(e *Event) {
+	st.Lock()
+	defer st.Unlock()
+
+	if st.done {
+		return
+	}
+	st.events = append(st.events, e)
+	if e.Type == EventCompleted {
+		st.done = true
+	}
+
+	close(st.notify)
+	st.notify = make(chan struct{})
+}
+
+// Since returns the Events after the first n, a channel which is closed
+// when more Events are available and whether the Stream has finished.
+func (st *Stream) Since(n int) ([]*Event, <-chan struct{}, bool) {
+	st.Lock()
+	defer st.Unlock()
+
+	if n < 0 {
+		n = 0
+	}
+	var events []*Event
+	if n < len(st.events) {
+		events = st.events[n:]
+	}
+
+	return events, st.notify, st.done
+}
+
+// Stream returns the Stream for a Search in progress, or nil if the Search
+// is not in memory
+func (sm *Manager) Stream(ID string) *Stream {
+	sm.RLock()
+	defer sm.RUnlock()
+	return sm.streams[ID]
+}
+
+// closeStream publishes the final Event for a Search and removes the Stream
+// once the retention period has passed
+func (sm *Manager) closeStream(ID string, e *Event) {
+	sm.RLock()
+	st, ok := sm.streams[ID]
+	sm.RUnlock()
+	if !ok {
+		return
+	}
+
+	st.publish(e)
+
+	time.AfterFunc(streamRetention, func() {
+		sm.Lock()
+		delete(sm.streams, ID)
+		sm.Unlock()
+	})
+}
+
+// LoadEvents rebuilds the Events of a completed Search from the DB, starting
+// from the Event with the given ID. Results are in the order they were found,
+// matching the IDs sent while the Search ran, and followed by the completed
+// Event. Searches saved without an order have their Results ordered by slug.
+func LoadEvents(ID string, from int) ([]*Event, error) {
+	b, err := db.GetSearch(ID)
+	if err != nil {
+		return nil, err
+	}
+	var srch Search
+	if err = srch.Unmarshal(b); err != nil {
+		return nil, err
+	}
+
+	b, err = db.GetSummary(ID)
+	if err != nil {
+		return nil, err
+	}
+	var summary Summary
+	if err = summary.Unmarshal(b); err != nil {
+		return nil, err
+	}
+
+	var order []string
+	if b, err = db.GetResultOrder(ID); err == nil {
+		json.Unmarshal(b, &order)
+	}
+	keys := resultOrder(order, summary.List)
+	if from < 0 {
+		from = 0
+	}
+	start := from
+	if start > len(keys) {
+		start = len(keys)
+	}
+
+	events := make([]*Event, 0, len(keys)+1-start)
+	for _, key := range keys[start:] {
+		e := &Event{
+			Type:   EventResult,
+			Result: summary.List[key],
+			Status: srch.Status,
+		}
+		if b, err := db.GetMatches(ID, key); err == nil {
+			var matches Matches
+			if err = matches.Unmarshal(b); err == nil {
+				e.Matches = &matches
+			}
+		}
+		events = append(events, e)
+	}
+
+	if from <= len(keys) {
+		events = append(events, &Event{
+			Type:   EventCompleted,
+			Status: srch.Status,
+			Total:  srch.Matches,
+		})
+	}
+
+	return events, nil
+}
+
+// resultOrder returns the keys of the Results in the order they were found,
+// followed by any missing from the order sorted by key
+func resultOrder(order []string, list map[string]*Result) []string {
+	keys := make([]string, 0, len(list))
+	seen := make(map[string]bool, len(list))
+	for _, key := range order {
+		if _, ok := list[key]; ok && !seen[key] {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+
+	var rest []string
+	for key := range list {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(keys, rest...)
+}
@@ -0,0 +1,69 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStreamSince(t *testing.T) {
+	st := newStream()
+
+	events, wait, done := st.Since(0)
+	if len(events) != 0 || done {
+		t.Errorf("Expected empty open stream got %d events, done %t", len(events), done)
+	}
+
+	st.publish(&Event{Type: EventResult, Result: &Result{Slug: "hello-dolly"}})
+
+	select {
+	case <-wait:
+	default:
+		t.Errorf("Expected subscribers to be notified of new events")
+	}
+
+	st.publish(&Event{Type: EventResult, Result: &Result{Slug: "akismet"}})
+	st.publish(&Event{Type: EventCompleted, Status: Completed})
+	st.publish(&Event{Type: EventResult, Result: &Result{Slug: "ignored"}})
+
+	events, _, done = st.Since(1)
+	if !done {
+		t.Errorf("Expected stream to be done")
+	}
+	want := []string{EventResult, EventCompleted}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events got %d", len(want), len(events))
+	}
+	for i, e := range events {
+		if e.Type != want[i] {
+			t.Errorf("Expected event %s got %s", want[i], e.Type)
+		}
+	}
+	if events[0].Result.Slug != "akismet" {
+		t.Errorf("Expected slug akismet got %s", events[0].Result.Slug)
+	}
+}
+
+func TestResultOrder(t *testing.T) {
+	list := map[string]*Result{
+		"akismet":     {Slug: "akismet"},
+		"hello-dolly": {Slug: "hello-dolly"},
+		"jetpack":     {Slug: "jetpack"},
+		"astra":       {Slug: "astra"},
+	}
+
+	tests := []struct {
+		order    []string
+		expected []string
+	}{
+		{nil, []string{"akismet", "astra", "hello-dolly", "jetpack"}},
+		{[]string{"jetpack", "akismet", "hello-dolly", "astra"}, []string{"jetpack", "akismet", "hello-dolly", "astra"}},
+		{[]string{"jetpack", "missing", "jetpack"}, []string{"jetpack", "akismet", "astra", "hello-dolly"}},
+	}
+
+	for _, tt := range tests {
+		keys := resultOrder(tt.order, list)
+		if strings.Join(keys, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("Expected %+v got %+v", tt.expected, keys)
+		}
+	}
+}
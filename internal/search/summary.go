@@ -0,0 +1,51 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// SortInstalls orders Results by active installs
+	SortInstalls = "installs"
+	// SortMatches orders Results by number of matches
+	SortMatches = "matches"
+	// SortSlug orders Results by slug
+	SortSlug = "slug"
+)
+
+// SortResults orders Results by the given field, ties are ordered by slug
+// and then repository so the order is stable between requests
+func SortResults(results []*Result, by string, desc bool) error {
+	var less func(a, b *Result) bool
+	switch by {
+	case SortInstalls:
+		less = func(a, b *Result) bool { return a.ActiveInstalls < b.ActiveInstalls }
+	case SortMatches:
+		less = func(a, b *Result) bool { return a.Matches < b.Matches }
+	case SortSlug:
+		less = func(a, b *Result) bool { return a.Slug < b.Slug }
+	default:
+		return fmt.Errorf("unknown sort field: %s", by)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		x, y := a, b
+		if desc {
+			x, y = b, a
+		}
+		if less(x, y) {
+			return true
+		}
+		if less(y, x) {
+			return false
+		}
+		if a.Slug != b.Slug {
+			return a.Slug < b.Slug
+		}
+		return a.Repo < b.Repo
+	})
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package search
+
+import (
+	"testing"
+)
+
+func TestSortResults(t *testing.T) {
+	results := func() []*Result {
+		return []*Result{
+			{Slug: "hello-dolly", ActiveInstalls: 600000, Matches: 1, Repo: "plugins"},
+			{Slug: "akismet", ActiveInstalls: 5000000, Matches: 4, Repo: "plugins"},
+			{Slug: "astra", ActiveInstalls: 600000, Matches: 9, Repo: "themes"},
+			{Slug: "akismet", ActiveInstalls: 10, Matches: 4, Repo: "themes"},
+		}
+	}
+
+	tests := []struct {
+		by   string
+		desc bool
+		want []string
+	}{
+		{SortInstalls, true, []string{"plugins/akismet", "themes/astra", "plugins/hello-dolly", "themes/akismet"}},
+		{SortInstalls, false, []string{"themes/akismet", "themes/astra", "plugins/hello-dolly", "plugins/akismet"}},
+		{SortMatches, true, []string{"themes/astra", "plugins/akismet", "themes/akismet", "plugins/hello-dolly"}},
+		{SortSlug, false, []string{"plugins/akismet", "themes/akismet", "themes/astra", "plugins/hello-dolly"}},
+		{SortSlug, true, []string{"plugins/hello-dolly", "themes/astra", "plugins/akismet", "themes/akismet"}},
+	}
+
+	for _, test := range tests {
+		list := results()
+		if err := SortResults(list, test.by, test.desc); err != nil {
+			t.Fatalf("Could not sort by %s: %s", test.by, err)
+		}
+		for i, r := range list {
+			if got := r.Repo + "/" + r.Slug; got != test.want[i] {
+				t.Errorf("Expected %+v at %d got %s sorting by %s", test.want, i, got, test.by)
+				break
+			}
+		}
+	}
+
+	if err := SortResults(results(), "rating", false); err == nil {
+		t.Errorf("Expected an error for an unknown sort field")
+	}
+}
@@ -0,0 +1,588 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/wpdirectory/wpdir/internal/db"
+	"github.com/wpdirectory/wpdir/internal/repo"
+	"github.com/wpdirectory/wpdir/internal/ulid"
+	"github.com/wpdirectory/wpdir/internal/webhook"
+)
+
+// MinWatchInterval is the shortest time allowed between runs of a Watch,
+// matching how often RunWatches is scheduled
+const MinWatchInterval = time.Minute
+
+// ErrWatchNotFound is returned when no Watch exists with the given ID
+var ErrWatchNotFound = errors.New("Watch not found")
+
+// NewWatch saves a Search to be run again every interval
+// The first run is started by RunWatches and used as the baseline later
+// runs are compared against.
+func (sm *Manager) NewWatch(name string, sr Request, interval time.Duration) (*Watch, error) {
+	opts := sr.Opts
+	w := &Watch{
+		ID:       ulid.New(),
+		Name:     name,
+		Input:    sr.Input,
+		Repo:     sr.Repo,
+		Repos:    sr.Repos,
+		Options:  &opts,
+		Interval: int64(interval / time.Second),
+		Created:  time.Now().Format(time.RFC3339),
+	}
+
+	sm.watchMu.Lock()
+	defer sm.watchMu.Unlock()
+
+	return w, saveWatch(w)
+}
+
+// GetWatch returns the Watch with the given ID
+func (sm *Manager) GetWatch(ID string) (*Watch, error) {
+	b, err := db.GetWatch(ID)
+	if err != nil {
+		return nil, ErrWatchNotFound
+	}
+	var w Watch
+	if err = w.Unmarshal(b); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// Watches returns every saved Watch
+func (sm *Manager) Watches() ([]*Watch, error) {
+	list, err := db.GetWatches()
+	if err != nil {
+		return nil, err
+	}
+
+	watches := make([]*Watch, 0, len(list))
+	for _, b := range list {
+		var w Watch
+		if err = w.Unmarshal(b); err != nil {
+			return nil, err
+		}
+		watches = append(watches, &w)
+	}
+	return watches, nil
+}
+
+// DeleteWatch removes a Watch, the Searches it ran are kept
+func (sm *Manager) DeleteWatch(ID string) error {
+	sm.watchMu.Lock()
+	defer sm.watchMu.Unlock()
+
+	if _, err := db.GetWatch(ID); err != nil {
+		return ErrWatchNotFound
+	}
+	return db.DeleteWatch(ID)
+}
+
+// WatchChanges returns the changes found by a Watch, newest first
+func (sm *Manager) WatchChanges(ID string) ([]*WatchChanges, error) {
+	list, err := db.GetWatchChanges(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*WatchChanges, 0, len(list))
+	for _, b := range list {
+		var c WatchChanges
+		if err = c.Unmarshal(b); err != nil {
+			return nil, err
+		}
+		changes = append(changes, &c)
+	}
+	return changes, nil
+}
+
+// RunWatches starts a Search for each Watch which is due to run
+// Watches with a Search still queued or running are skipped. The Searches
+// are created without holding the Watch lock, as finishing Searches need it.
+func (sm *Manager) RunWatches() {
+	if !sm.IsLoaded() {
+		return
+	}
+
+	now := time.Now()
+	due := sm.dueWatches(now)
+
+	started := make(map[string]string, len(due))
+	for _, w := range due {
+		opts := Options{}
+		if w.Options != nil {
+			opts = *w.Options
+		}
+		searchID, err := sm.NewSearch(Request{
+			Input:   w.Input,
+			Repo:    w.Repo,
+			Repos:   w.Repos,
+			Private: true,
+			Opts:    opts,
+			WatchID: w.ID,
+		})
+		if err != nil {
+			// Try again on the next run
+			log.Printf("Failed starting Search for Watch %s: %s\n", w.ID, err)
+			continue
+		}
+		started[w.ID] = searchID
+	}
+	if len(started) == 0 {
+		return
+	}
+
+	sm.watchMu.Lock()
+	defer sm.watchMu.Unlock()
+
+	for watchID, searchID := range started {
+		// The Watch may have been changed or deleted meanwhile
+		w, err := sm.GetWatch(watchID)
+		if err != nil {
+			continue
+		}
+		w.PendingSearch = searchID
+		w.LastRun = now.Format(time.RFC3339)
+
+		if err = saveWatch(w); err != nil {
+			log.Printf("Failed saving Watch %s: %s\n", w.ID, err)
+		}
+	}
+}
+
+// dueWatches returns the Watches due to run which have no Search queued or
+// running
+func (sm *Manager) dueWatches(now time.Time) []*Watch {
+	sm.watchMu.Lock()
+	defer sm.watchMu.Unlock()
+
+	watches, err := sm.Watches()
+	if err != nil {
+		log.Printf("Failed loading Watches: %s\n", err)
+		return nil
+	}
+
+	var due []*Watch
+	for _, w := range watches {
+		if w.PendingSearch != "" {
+			if sm.Exists(w.PendingSearch) {
+				continue
+			}
+			// The Search was cancelled or lost in a restart
+			w.PendingSearch = ""
+		}
+		if w.due(now) {
+			due = append(due, w)
+		}
+	}
+	return due
+}
+
+// WatchEvents returns the events recorded for a Watch, newest first
+func (sm *Manager) WatchEvents(ID string) ([]*WatchEvent, error) {
+	list, err := db.GetWatchEvents(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*WatchEvent, 0, len(list))
+	for _, b := range list {
+		var e WatchEvent
+		if err = e.Unmarshal(b); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, nil
+}
+
+// CheckUpdate runs the Watches over a Repository against an Extension whose
+// new index has just been swapped in. Matched lines not found in the
+// previous version are recorded as a WatchEvent. Watches which have not yet
+// completed a run have nothing to compare against and are skipped.
+func (sm *Manager) CheckUpdate(repoName string, e *repo.Extension, oldVersion string) {
+	watches, err := sm.Watches()
+	if err != nil {
+		log.Printf("Failed loading Watches: %s\n", err)
+		return
+	}
+
+	e.RLock()
+	slug := e.Slug
+	version := e.Version
+	e.RUnlock()
+
+	for _, w := range watches {
+		if w.LastSearch == "" || !w.covers(repoName) {
+			continue
+		}
+
+		var filters *Filters
+		if w.Options != nil {
+			filters = w.Options.Filters
+		}
+		flt, err := newFilter(filters)
+		if err != nil || (flt != nil && !flt.match(e)) {
+			continue
+		}
+
+		ctx := context.Background()
+		var cancel context.CancelFunc = func() {}
+		if sm.timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, sm.timeout)
+		}
+		resp, err := e.Search(ctx, w.Input, slug, indexOptions(w.Options))
+		if err != nil || resp == nil || len(resp.Matches) == 0 {
+			cancel()
+			continue
+		}
+
+		prev, err := sm.previousMatches(ctx, w, repoName, e, oldVersion)
+		cancel()
+		if err != nil {
+			log.Printf("Failed loading Watch %s matches: %s\n", w.ID, err)
+			continue
+		}
+		matches := newLines(prev, newMatches(slug, resp, sm.lineLength).List)
+		if len(matches) == 0 {
+			continue
+		}
+		for _, m := range matches {
+			m.Repo = repoName
+		}
+
+		event := &WatchEvent{
+			ID:         ulid.New(),
+			WatchID:    w.ID,
+			Repo:       repoName,
+			Slug:       slug,
+			OldVersion: oldVersion,
+			NewVersion: version,
+			Pattern:    w.Input,
+			Created:    time.Now().Format(time.RFC3339),
+			Matches:    matches,
+		}
+		if err = saveWatchEvent(event); err != nil {
+			log.Printf("Failed saving Watch %s event: %s\n", w.ID, err)
+			continue
+		}
+		log.Printf("Watch %s found %d new matches in %s %s\n", w.ID, len(matches), slug, version)
+
+		webhook.Send(webhook.WatchMatch, &watchMatch{
+			WatchID: w.ID,
+			Name:    w.Name,
+			Event:   event,
+		})
+	}
+}
+
+// watchMatch is the webhook payload sent when a Watch finds new Matches,
+// either the changes found by a scheduled run or the event recorded when an
+// Extension was updated
+type watchMatch struct {
+	WatchID string        `json:"watch_id"`
+	Name    string        `json:"name,omitempty"`
+	Changes *WatchChanges `json:"changes,omitempty"`
+	Event   *WatchEvent   `json:"event,omitempty"`
+}
+
+// covers reports whether the Watch searches the Repository
+func (w *Watch) covers(repoName string) bool {
+	srch := Search{Repo: w.Repo, Repos: w.Repos}
+	for _, name := range srch.RepoNames() {
+		if name == repoName {
+			return true
+		}
+	}
+	return false
+}
+
+// previousMatches returns the Matches of the version of an Extension before
+// an update. The index of the old version is searched when it is still
+// retained, otherwise the Matches found by the last run of the Watch are
+// used along with those of any events recorded for the Extension since.
+func (sm *Manager) previousMatches(ctx context.Context, w *Watch, repoName string, e *repo.Extension, oldVersion string) ([]*Match, error) {
+	e.RLock()
+	slug := e.Slug
+	version := e.Version
+	e.RUnlock()
+
+	if oldVersion != "" && oldVersion != version {
+		resps, err := e.SearchVersions(ctx, w.Input, slug, oldVersion, indexOptions(w.Options))
+		if err != nil {
+			return nil, err
+		}
+		if len(resps) > 0 {
+			return newMatches(slug, resps[0], sm.lineLength).List, nil
+		}
+	}
+
+	prev, err := w.lastRunMatches(repoName, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := sm.WatchEvents(w.ID)
+	if err != nil {
+		return nil, err
+	}
+	since, _ := time.Parse(time.RFC3339, w.LastRun)
+	return append(prev, eventMatches(events, repoName, slug, since)...), nil
+}
+
+// lastRunMatches returns the Matches of an Extension found by the last run
+// of the Watch, an Extension without Matches returns none
+func (w *Watch) lastRunMatches(repoName, slug string) ([]*Match, error) {
+	srch := Search{Repo: w.Repo, Repos: w.Repos}
+	b, err := db.GetMatches(w.LastSearch, srch.ResultKey(repoName, slug))
+	if err != nil {
+		return nil, nil
+	}
+
+	var matches Matches
+	if err = matches.Unmarshal(b); err != nil {
+		return nil, err
+	}
+	return matches.List, nil
+}
+
+// eventMatches returns the Matches of the events recorded for an Extension
+// since a time, so lines are only reported once between runs of a Watch
+func eventMatches(events []*WatchEvent, repoName, slug string, since time.Time) []*Match {
+	var matches []*Match
+	for _, ev := range events {
+		if ev.Repo != repoName || ev.Slug != slug {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339, ev.Created)
+		if err != nil || created.Before(since) {
+			continue
+		}
+		matches = append(matches, ev.Matches...)
+	}
+	return matches
+}
+
+// newLines returns the Matches in cur on lines not matched in prev
+// Lines are compared by file and text, as line numbers change between
+// versions of an Extension.
+func newLines(prev, cur []*Match) []*Match {
+	seen := make(map[string]bool, len(prev))
+	for _, m := range prev {
+		seen[m.File+"\x00"+m.LineText] = true
+	}
+
+	var added []*Match
+	for _, m := range cur {
+		if !seen[m.File+"\x00"+m.LineText] {
+			added = append(added, m)
+		}
+	}
+	return added
+}
+
+// due reports whether the Watch should be run again
+func (w *Watch) due(now time.Time) bool {
+	if w.LastRun == "" {
+		return true
+	}
+	last, err := time.Parse(time.RFC3339, w.LastRun)
+	if err != nil {
+		return true
+	}
+	return !now.Before(last.Add(time.Duration(w.Interval) * time.Second))
+}
+
+// finishWatch compares a finished Search run by a Watch with the previous
+// run and saves the Matches added and removed
+// Only completed Searches are compared, as cancelled or timed out Searches
+// may be missing Matches.
+func (sm *Manager) finishWatch(srch Search) {
+	if srch.WatchID == "" {
+		return
+	}
+
+	sm.watchMu.Lock()
+	defer sm.watchMu.Unlock()
+
+	w, err := sm.GetWatch(srch.WatchID)
+	if err != nil {
+		// The Watch was deleted while the Search ran
+		return
+	}
+	if w.PendingSearch == srch.ID {
+		w.PendingSearch = ""
+	}
+
+	if srch.Status == Completed {
+		if w.LastSearch != "" {
+			changes, err := compareSearches(w.LastSearch, srch.ID)
+			if err != nil {
+				log.Printf("Failed comparing Watch %s: %s\n", w.ID, err)
+			} else if len(changes.Added) > 0 || len(changes.Removed) > 0 {
+				changes.WatchID = w.ID
+				changes.Created = srch.Completed
+				if err = saveWatchChanges(changes); err != nil {
+					log.Printf("Failed saving Watch %s changes: %s\n", w.ID, err)
+				}
+				if len(changes.Added) > 0 {
+					webhook.Send(webhook.WatchMatch, &watchMatch{
+						WatchID: w.ID,
+						Name:    w.Name,
+						Changes: changes,
+					})
+				}
+			}
+		}
+		w.LastSearch = srch.ID
+	}
+
+	if err = saveWatch(w); err != nil {
+		log.Printf("Failed saving Watch %s: %s\n", w.ID, err)
+	}
+}
+
+// compareSearches finds the Matches added and removed between two Searches
+func compareSearches(previousID, ID string) (*WatchChanges, error) {
+	prev, err := loadMatches(previousID)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := loadMatches(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffMatches(prev, cur)
+	changes.SearchID = ID
+	changes.PreviousID = previousID
+	return changes, nil
+}
+
+// loadMatches reads every Match of a saved Search, with the Repository
+// each was found in
+func loadMatches(ID string) ([]*Match, error) {
+	b, err := db.GetSearch(ID)
+	if err != nil {
+		return nil, err
+	}
+	var srch Search
+	if err = srch.Unmarshal(b); err != nil {
+		return nil, err
+	}
+
+	b, err = db.GetSummary(ID)
+	if err != nil {
+		return nil, err
+	}
+	var summary Summary
+	if err = summary.Unmarshal(b); err != nil {
+		return nil, err
+	}
+
+	var list []*Match
+	err = db.ForEachMatches(ID, func(key string, data []byte) error {
+		var matches Matches
+		if err := matches.Unmarshal(data); err != nil {
+			return err
+		}
+
+		repoName := srch.Repo
+		if res := summary.List[key]; res != nil && res.Repo != "" {
+			repoName = res.Repo
+		}
+		for _, m := range matches.List {
+			m.Repo = repoName
+			list = append(list, m)
+		}
+		return nil
+	})
+	return list, err
+}
+
+// diffMatches compares two runs of a Watch by Repository, slug, file and
+// line number
+func diffMatches(prev, cur []*Match) *WatchChanges {
+	seen := make(map[string]bool, len(prev))
+	for _, m := range prev {
+		seen[matchKey(m)] = true
+	}
+	found := make(map[string]bool, len(cur))
+	for _, m := range cur {
+		found[matchKey(m)] = true
+	}
+
+	changes := &WatchChanges{}
+	for _, m := range cur {
+		if !seen[matchKey(m)] {
+			changes.Added = append(changes.Added, m)
+		}
+	}
+	for _, m := range prev {
+		if !found[matchKey(m)] {
+			changes.Removed = append(changes.Removed, m)
+		}
+	}
+	sortMatches(changes.Added)
+	sortMatches(changes.Removed)
+
+	return changes
+}
+
+// matchKey identifies the line a Match was found on, including the version
+// when past versions were searched
+func matchKey(m *Match) string {
+	slug := m.Slug
+	if m.Version != "" {
+		slug += "@" + m.Version
+	}
+	return fmt.Sprintf("%s/%s/%s:%d", m.Repo, slug, m.File, m.LineNum)
+}
+
+// sortMatches orders Matches by Repository, slug, file and line number
+func sortMatches(list []*Match) {
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Repo != b.Repo {
+			return a.Repo < b.Repo
+		}
+		if a.Slug != b.Slug {
+			return a.Slug < b.Slug
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.LineNum < b.LineNum
+	})
+}
+
+func saveWatch(w *Watch) error {
+	b, err := w.Marshal()
+	if err != nil {
+		return errors.New("Failed Marshalling Watch")
+	}
+	return db.SaveWatch(w.ID, b)
+}
+
+func saveWatchChanges(c *WatchChanges) error {
+	b, err := c.Marshal()
+	if err != nil {
+		return errors.New("Failed Marshalling Watch changes")
+	}
+	return db.SaveWatchChanges(c.WatchID, c.SearchID, b)
+}
+
+func saveWatchEvent(e *WatchEvent) error {
+	b, err := e.Marshal()
+	if err != nil {
+		return errors.New("Failed Marshalling Watch event")
+	}
+	return db.SaveWatchEvent(e.WatchID, e.ID, b)
+}
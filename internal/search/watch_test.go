@@ -0,0 +1,115 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffMatches(t *testing.T) {
+	prev := []*Match{
+		{Repo: "plugins", Slug: "akismet", File: "akismet.php", LineNum: 10},
+		{Repo: "plugins", Slug: "akismet", File: "akismet.php", LineNum: 20},
+		{Repo: "themes", Slug: "astra", File: "functions.php", LineNum: 5},
+	}
+	cur := []*Match{
+		{Repo: "plugins", Slug: "akismet", File: "akismet.php", LineNum: 20},
+		{Repo: "plugins", Slug: "akismet", File: "akismet.php", LineNum: 30},
+		{Repo: "plugins", Slug: "astra", File: "functions.php", LineNum: 5},
+		{Repo: "themes", Slug: "astra", File: "functions.php", LineNum: 5},
+	}
+
+	changes := diffMatches(prev, cur)
+
+	var added, removed []string
+	for _, m := range changes.Added {
+		added = append(added, matchKey(m))
+	}
+	for _, m := range changes.Removed {
+		removed = append(removed, matchKey(m))
+	}
+
+	wantAdded := []string{"plugins/akismet/akismet.php:30", "plugins/astra/functions.php:5"}
+	wantRemoved := []string{"plugins/akismet/akismet.php:10"}
+	if !reflect.DeepEqual(added, wantAdded) {
+		t.Errorf("Expected %+v got %+v", wantAdded, added)
+	}
+	if !reflect.DeepEqual(removed, wantRemoved) {
+		t.Errorf("Expected %+v got %+v", wantRemoved, removed)
+	}
+}
+
+func TestWatchDue(t *testing.T) {
+	now := time.Date(2018, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		lastRun string
+		due     bool
+	}{
+		{"", true},
+		{now.Add(-30 * time.Minute).Format(time.RFC3339), false},
+		{now.Add(-time.Hour).Format(time.RFC3339), true},
+		{"invalid", true},
+	}
+
+	for _, test := range tests {
+		w := &Watch{Interval: 3600, LastRun: test.lastRun}
+		if w.due(now) != test.due {
+			t.Errorf("Expected %+v got %+v for %s", test.due, w.due(now), test.lastRun)
+		}
+	}
+}
+
+func TestNewLines(t *testing.T) {
+	prev := []*Match{
+		{File: "admin.php", LineNum: 10, LineText: "eval( $code );"},
+		{File: "admin.php", LineNum: 20, LineText: "eval( $other );"},
+	}
+	cur := []*Match{
+		{File: "admin.php", LineNum: 12, LineText: "eval( $code );"},
+		{File: "admin.php", LineNum: 30, LineText: "eval(base64_decode( $x ));"},
+		{File: "inc/load.php", LineNum: 20, LineText: "eval( $other );"},
+	}
+
+	var got []string
+	for _, m := range newLines(prev, cur) {
+		got = append(got, matchKey(m))
+	}
+
+	want := []string{"//admin.php:30", "//inc/load.php:20"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v got %+v", want, got)
+	}
+}
+
+func TestEventMatches(t *testing.T) {
+	since := time.Date(2018, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	events := []*WatchEvent{
+		{Repo: "plugins", Slug: "akismet", Created: since.Add(time.Hour).Format(time.RFC3339), Matches: []*Match{
+			{File: "admin.php", LineNum: 30},
+		}},
+		{Repo: "plugins", Slug: "akismet", Created: since.Add(-time.Hour).Format(time.RFC3339), Matches: []*Match{
+			{File: "admin.php", LineNum: 20},
+		}},
+		{Repo: "themes", Slug: "akismet", Created: since.Add(time.Hour).Format(time.RFC3339), Matches: []*Match{
+			{File: "functions.php", LineNum: 5},
+		}},
+		{Repo: "plugins", Slug: "hello-dolly", Created: since.Format(time.RFC3339), Matches: []*Match{
+			{File: "hello.php", LineNum: 1},
+		}},
+		{Repo: "plugins", Slug: "akismet", Created: since.Format(time.RFC3339), Matches: []*Match{
+			{File: "load.php", LineNum: 10},
+		}},
+	}
+
+	var got []string
+	for _, m := range eventMatches(events, "plugins", "akismet", since) {
+		got = append(got, matchKey(m))
+	}
+
+	want := []string{"//admin.php:30", "//load.php:10"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v got %+v", want, got)
+	}
+}
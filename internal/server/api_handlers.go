@@ -8,13 +8,33 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
+	"github.com/wpdirectory/wpdir/internal/codesearch/regexp"
 	"github.com/wpdirectory/wpdir/internal/db"
+	"github.com/wpdirectory/wpdir/internal/index"
 	"github.com/wpdirectory/wpdir/internal/repo"
 	"github.com/wpdirectory/wpdir/internal/search"
 )
 
+const (
+	// defaultLinesOfContext is used when a search request does not specify
+	// how many lines should be stored either side of a match
+	defaultLinesOfContext = 2
+	// maxLinesOfContext is the most lines of context a search may request
+	maxLinesOfContext = 10
+	// streamWindow is how long a single streaming connection is held open
+	// it must be shorter than the HTTP server WriteTimeout
+	streamWindow = 8 * time.Second
+	// streamRetry is the reconnection delay sent to streaming clients
+	streamRetry = 1000
+	// exportWindow is how long an export may take to be written, in place of
+	// the HTTP server WriteTimeout
+	exportWindow = 10 * time.Minute
+)
+
 type errResponse struct {
 	Code string `json:"code,omitempty"`
 	Err  string `json:"error"`
@@ -75,7 +95,7 @@ func (s *Server) getLoaded() http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		var resp getLoadedResponse
-		
+
 		resp.Loaded = s.Manager.IsLoaded()
 
 		writeResp(w, resp)
@@ -86,17 +106,19 @@ func (s *Server) getLoaded() http.HandlerFunc {
 // First we check for inprogress searches in memory, then we check in the DB
 func (s *Server) getSearch() http.HandlerFunc {
 	type getSearchResponse struct {
-		ID        string               `json:"id"`
-		Input     string               `json:"input"`
-		Repo      string               `json:"repo"`
-		Matches   uint32               `json:"matches"`
-		Started   string               `json:"started,omitempty"`
-		Completed string               `json:"completed,omitempty"`
-		Progress  uint32               `json:"progress"`
-		Status    search.Search_Status `json:"status"`
-		Behind    uint32               `json:"behind"`
-		QueuePos  int                  `json:"queue_pos"`
-		Opts      search.Options       `json:"options"`
+		ID          string               `json:"id"`
+		Input       string               `json:"input"`
+		Repo        string               `json:"repo"`
+		Matches     uint32               `json:"matches"`
+		Started     string               `json:"started,omitempty"`
+		Completed   string               `json:"completed,omitempty"`
+		Progress    uint32               `json:"progress"`
+		Status      search.Search_Status `json:"status"`
+		Behind      uint32               `json:"behind"`
+		QueuePos    int                  `json:"queue_pos"`
+		Opts        search.Options       `json:"options"`
+		Repos       []string             `json:"repos,omitempty"`
+		BehindRepos map[string]uint32    `json:"behind_repos,omitempty"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -107,21 +129,6 @@ func (s *Server) getSearch() http.HandlerFunc {
 				var resp getSearchResponse
 				srch := s.Manager.Get(searchID)
 
-				old := srch.Revision
-				var cur uint32
-				switch srch.Repo {
-				case "plugins":
-					s.Manager.Plugins.RLock()
-					cur = uint32(s.Manager.Plugins.Revision)
-					s.Manager.Plugins.RUnlock()
-				case "themes":
-					s.Manager.Themes.RLock()
-					cur = uint32(s.Manager.Plugins.Revision)
-					s.Manager.Themes.RUnlock()
-				default:
-					// error
-				}
-
 				resp.ID = srch.ID
 				resp.Input = srch.Input
 				resp.Repo = srch.Repo
@@ -130,9 +137,10 @@ func (s *Server) getSearch() http.HandlerFunc {
 				resp.Completed = srch.Completed
 				resp.Progress = srch.Progress
 				resp.Status = srch.Status
-				resp.Behind = cur - old
+				resp.Behind, resp.BehindRepos = s.searchBehind(&srch)
 				resp.QueuePos = s.Manager.Queue.Pos(searchID)
 				resp.Opts = *srch.Options
+				resp.Repos = srch.Repos
 
 				writeResp(w, resp)
 				return
@@ -158,23 +166,8 @@ func (s *Server) getSearch() http.HandlerFunc {
 				return
 			}
 
-			old := srch.Revision
-			var cur uint32
-			switch srch.Repo {
-			case "plugins":
-				s.Manager.Plugins.RLock()
-				cur = uint32(s.Manager.Plugins.Revision)
-				s.Manager.Plugins.RUnlock()
-			case "themes":
-				s.Manager.Themes.RLock()
-				cur = uint32(s.Manager.Plugins.Revision)
-				s.Manager.Themes.RUnlock()
-			default:
-					// error
-			}
-
 			var resp getSearchResponse
-			
+
 			resp.ID = srch.ID
 			resp.Input = srch.Input
 			resp.Repo = srch.Repo
@@ -182,9 +175,10 @@ func (s *Server) getSearch() http.HandlerFunc {
 			resp.Started = srch.Started
 			resp.Completed = srch.Completed
 			resp.Progress = srch.Progress
-			resp.Status = search.Completed
-			resp.Behind = cur - old
+			resp.Status = srch.Status
+			resp.Behind, resp.BehindRepos = s.searchBehind(&srch)
 			resp.Opts = *srch.Options
+			resp.Repos = srch.Repos
 
 			writeResp(w, resp)
 		} else {
@@ -196,16 +190,89 @@ func (s *Server) getSearch() http.HandlerFunc {
 	}
 }
 
+// searchBehind returns how many revisions each searched Repository has
+// moved on since the Search ran, along with the largest of them
+func (s *Server) searchBehind(srch *search.Search) (uint32, map[string]uint32) {
+	revisions := srch.Revisions
+	if len(revisions) == 0 {
+		revisions = map[string]uint32{srch.Repo: srch.Revision}
+	}
+
+	var max uint32
+	behind := make(map[string]uint32, len(revisions))
+	for name, old := range revisions {
+		r := s.Manager.Repo(name)
+		// Queued Searches have not recorded a revision yet
+		if r == nil || old == 0 {
+			continue
+		}
+		r.RLock()
+		cur := uint32(r.Revision)
+		r.RUnlock()
+
+		if cur > old {
+			behind[name] = cur - old
+		} else {
+			behind[name] = 0
+		}
+		if behind[name] > max {
+			max = behind[name]
+		}
+	}
+
+	return max, behind
+}
+
 // getSearchSummary returns a Summary of the Search results
+// Results are sorted with ?sort=installs|matches|slug&order=asc|desc and
+// paginated when page or per_page are given, with Link headers to the
+// other pages.
 func (s *Server) getSearchSummary() http.HandlerFunc {
 	type getSearchSummaryResponse struct {
 		Results []*search.Result `json:"results"`
+		Total   int              `json:"total"`
+		Matches uint64           `json:"matches"`
+		Page    int              `json:"page,omitempty"`
+		PerPage int              `json:"per_page,omitempty"`
+		Pages   int              `json:"pages,omitempty"`
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		searchID := chi.URLParam(r, "id")
 
 		if searchID != "" {
 			var resp getSearchSummaryResponse
+			query := r.URL.Query()
+
+			sortBy := query.Get("sort")
+			if sortBy == "" {
+				sortBy = search.SortInstalls
+			}
+			// Numbers are largest first and slugs alphabetical by default
+			desc := sortBy != search.SortSlug
+			switch query.Get("order") {
+			case "":
+				break
+			case "asc":
+				desc = false
+			case "desc":
+				desc = true
+			default:
+				var resp errResponse
+				resp.Err = "Please provide a valid order (asc or desc)."
+				w.WriteHeader(http.StatusBadRequest)
+				writeResp(w, resp)
+				return
+			}
+
+			page, perPage, ok := pageParams(query)
+			if !ok {
+				var resp errResponse
+				resp.Err = fmt.Sprintf("Please provide a valid page and a per_page between 1 and %d.", maxPerPage)
+				w.WriteHeader(http.StatusBadRequest)
+				writeResp(w, resp)
+				return
+			}
+
 			bytes, err := db.GetSummary(searchID)
 			if err != nil || bytes == nil {
 				var resp errResponse
@@ -225,8 +292,31 @@ func (s *Server) getSearchSummary() http.HandlerFunc {
 				return
 			}
 
+			results := make([]*search.Result, 0, len(summary.List))
 			for _, result := range summary.List {
-				resp.Results = append(resp.Results, result)
+				results = append(results, result)
+				resp.Matches += uint64(result.Matches)
+			}
+			if err := search.SortResults(results, sortBy, desc); err != nil {
+				var resp errResponse
+				resp.Err = "Please provide a valid sort (installs, matches or slug)."
+				w.WriteHeader(http.StatusBadRequest)
+				writeResp(w, resp)
+				return
+			}
+			resp.Total = len(results)
+			resp.Results = results
+
+			if perPage > 0 {
+				start, end, pages := pageBounds(len(results), page, perPage)
+				resp.Page = page
+				resp.PerPage = perPage
+				resp.Pages = pages
+				resp.Results = results[start:end]
+
+				if link := linkHeader(r.URL, page, resp.Pages); link != "" {
+					w.Header().Set("Link", link)
+				}
 			}
 
 			writeResp(w, resp)
@@ -239,14 +329,44 @@ func (s *Server) getSearchSummary() http.HandlerFunc {
 	}
 }
 
+// matchesKey returns the key the Matches of an Extension are saved under
+// Searches over several Repositories key them by Repository and slug, so
+// the Repository must be given, while others key them by slug alone.
+func matchesKey(searchID, repoName, slug string) (string, bool) {
+	b, err := db.GetSearch(searchID)
+	if err != nil {
+		return "", false
+	}
+	var srch search.Search
+	if err = srch.Unmarshal(b); err != nil {
+		return "", false
+	}
+
+	if len(srch.Repos) > 1 {
+		if repoName == "" {
+			return "", false
+		}
+		return srch.ResultKey(repoName, slug), true
+	}
+	if repoName != "" && repoName != srch.Repo {
+		return "", false
+	}
+	return slug, true
+}
+
 // getSearchMatches returns a list of Search matches for a given Extension
+// Searches over several Repositories include the Repository in the URL.
 func (s *Server) getSearchMatches() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		searchID := chi.URLParam(r, "id")
 		slug := chi.URLParam(r, "slug")
 
 		if searchID != "" && slug != "" {
-			bytes, err := db.GetMatches(searchID, slug)
+			var bytes []byte
+			var err error
+			if key, ok := matchesKey(searchID, chi.URLParam(r, "repo"), slug); ok {
+				bytes, err = db.GetMatches(searchID, key)
+			}
 			if err != nil || bytes == nil {
 				var resp errResponse
 				resp.Err = fmt.Sprintf("Matches not found for Search %s and Slug %s\n", searchID, slug)
@@ -275,12 +395,153 @@ func (s *Server) getSearchMatches() http.HandlerFunc {
 	}
 }
 
+// exportSearch streams every Match of a completed Search as CSV, JSON Lines
+// or SARIF, chosen with the format query parameter (csv by default)
+func (s *Server) exportSearch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		searchID := chi.URLParam(r, "id")
+		if searchID == "" {
+			var resp errResponse
+			resp.Err = "You must specify a valid Search ID."
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = search.ExportCSV
+		}
+		contentType, ok := search.ExportContentType(format)
+		if !ok {
+			var resp errResponse
+			resp.Err = "Please provide a valid format (csv, jsonl or sarif)."
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		if s.Manager.Exists(searchID) {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Search %s has not finished yet", searchID)
+			w.WriteHeader(http.StatusConflict)
+			writeResp(w, resp)
+			return
+		}
+
+		if _, err := db.GetSearch(searchID); err != nil {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Search %s not found", searchID)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		}
+
+		if err := extendWriteDeadline(r, exportWindow); err != nil {
+			s.Logger.Printf("Export of Search %s limited to the write timeout: %s\n", searchID, err)
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", searchID+"."+format))
+
+		// Headers have been sent, so errors can only be logged
+		if err := search.Export(w, searchID, format); err != nil {
+			s.Logger.Printf("Export of Search %s failed: %s\n", searchID, err)
+		}
+	}
+}
+
+// getSearchStream streams the results of a Search as Server-Sent Events
+// Each Extension's Result and Matches are sent as soon as they are found,
+// followed by a completed event. Connections are closed before the server
+// write timeout, clients resume by sending the Last-Event-ID header.
+func (s *Server) getSearchStream() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		searchID := chi.URLParam(r, "id")
+		if searchID == "" {
+			var resp errResponse
+			resp.Err = "You must specify a valid Search ID."
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			var resp errResponse
+			resp.Err = "Streaming is not supported."
+			w.WriteHeader(http.StatusInternalServerError)
+			writeResp(w, resp)
+			return
+		}
+
+		next := 0
+		if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+			if n, err := strconv.Atoi(lastID); err == nil {
+				next = n + 1
+			}
+		}
+
+		st := s.Manager.Stream(searchID)
+		if st == nil {
+			// Replay completed Searches from the DB, resuming after the
+			// last Event received, which has the same ID as when streamed
+			events, err := search.LoadEvents(searchID, next)
+			if err != nil {
+				var resp errResponse
+				resp.Err = fmt.Sprintf("Search %s not found", searchID)
+				w.WriteHeader(http.StatusNotFound)
+				writeResp(w, resp)
+				return
+			}
+
+			writeStreamHeaders(w)
+			deadline := time.Now().Add(streamWindow)
+			for _, e := range events {
+				if time.Now().After(deadline) {
+					break
+				}
+				writeEvent(w, next, e)
+				next++
+				flusher.Flush()
+			}
+			return
+		}
+
+		writeStreamHeaders(w)
+		flusher.Flush()
+
+		timeout := time.After(streamWindow)
+		for {
+			events, wait, done := st.Since(next)
+			for _, e := range events {
+				writeEvent(w, next, e)
+				next++
+			}
+			flusher.Flush()
+
+			if done {
+				return
+			}
+
+			select {
+			case <-wait:
+			case <-timeout:
+				return
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
+
 // getMatchFile returns the contents of a file identified by Repo, Slug and Filename
 func (s *Server) getMatchFile() http.HandlerFunc {
 	type getFileRequest struct {
-		Repo string `json:"repo"`
-		Slug string `json:"slug"`
-		File string `json:"file"`
+		Repo    string `json:"repo"`
+		Slug    string `json:"slug"`
+		Version string `json:"version"`
+		File    string `json:"file"`
 	}
 	type getFileResponse struct {
 		Code string `json:"code"`
@@ -301,7 +562,7 @@ func (s *Server) getMatchFile() http.HandlerFunc {
 
 		if data.Repo != "" && data.Slug != "" && data.File != "" {
 			var resp getFileResponse
-			path, err := s.getFilePath(data.Repo, data.Slug, data.File)
+			path, err := s.getFilePath(data.Repo, data.Slug, data.Version, data.File)
 			if err != nil {
 				var resp errResponse
 				resp.Err = "File could not be found"
@@ -346,15 +607,17 @@ func (s *Server) getMatchFile() http.HandlerFunc {
 // createSearch creates a new Search and returns the ID
 func (s *Server) createSearch() http.HandlerFunc {
 	type createSearchRequest struct {
-		Input   string `json:"input"`
-		Target  string `json:"target"`
-		Private bool   `json:"private"`
+		searchParams
+		Private bool `json:"private"`
 	}
 
 	type createSearchResponse struct {
-		Status int    `json:"status"`
-		ID     string `json:"id"`
-		Err    string `json:"error,omitempty"`
+		Status  int    `json:"status"`
+		ID      string `json:"id,omitempty"`
+		Token   string `json:"token,omitempty"`
+		Query   string `json:"query"`
+		Warning string `json:"warning,omitempty"`
+		Err     string `json:"error,omitempty"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -377,39 +640,314 @@ func (s *Server) createSearch() http.HandlerFunc {
 			panic(err)
 		}
 
-		// Ensure regex is not blank
-		// TODO: Are there other checks which should be made here to prevent abuse?
-		if data.Input == "" {
+		sr, analysis, msg := data.searchRequest(s.Manager.RepoNames())
+		if analysis == nil {
 			var resp errResponse
-			resp.Err = "Please provide non-blank search input."
+			resp.Err = msg
 			w.WriteHeader(http.StatusBadRequest)
 			writeResp(w, resp)
 			return
 		}
-
-		// Check Target
-		switch data.Target {
-		case "plugins":
-			break
-		case "themes":
-			break
-		default:
-			var resp errResponse
-			resp.Err = "Please provide a valid target"
+		resp.Query = analysis.Query
+		if msg != "" {
+			resp.Err = msg
 			w.WriteHeader(http.StatusBadRequest)
 			writeResp(w, resp)
 			return
 		}
+		if analysis.Weak() {
+			resp.Warning = "This search is only loosely filtered by the index and may be slow."
+		}
 
-		var sr search.Request
-		sr.Input = data.Input
-		sr.Repo = data.Target
 		sr.Private = data.Private
+		sr.Token = search.NewToken()
 
 		// Perform non-blocking Search...
-		id := s.Manager.NewSearch(sr)
+		id, err := s.Manager.NewSearch(sr)
+		if err != nil {
+			var resp errResponse
+			resp.Err = "Too many searches are waiting, please try again later."
+			w.WriteHeader(http.StatusServiceUnavailable)
+			writeResp(w, resp)
+			return
+		}
 
 		resp.ID = id
+		resp.Token = sr.Token
+		writeResp(w, resp)
+	}
+}
+
+// searchParams are the Search fields shared by new Searches and Watches
+type searchParams struct {
+	Input          string          `json:"input"`
+	Target         searchTargets   `json:"target"`
+	IgnoreCase     bool            `json:"ignore_case"`
+	LinesOfContext *uint32         `json:"lines_of_context"`
+	FileRegexp     string          `json:"file_regexp"`
+	IgnoreComments bool            `json:"ignore_comments"`
+	Offset         uint32          `json:"offset"`
+	Limit          uint32          `json:"limit"`
+	Mode           string          `json:"mode"`
+	Scope          string          `json:"scope"`
+	Filters        *search.Filters `json:"filters"`
+	Version        string          `json:"version"`
+}
+
+// searchRequest validates the params and converts them to a Search Request
+// targeting the named Repositories. If they are invalid a message explaining
+// why is returned. The Analysis is returned once the input has been parsed.
+func (p *searchParams) searchRequest(repoNames []string) (search.Request, *index.Analysis, string) {
+	var sr search.Request
+
+	// Ensure regex is not blank
+	// TODO: Are there other checks which should be made here to prevent abuse?
+	if p.Input == "" {
+		return sr, nil, "Please provide non-blank search input."
+	}
+
+	// Check Target
+	repos, ok := p.Target.repos(repoNames)
+	if !ok {
+		return sr, nil, "Please provide a valid target"
+	}
+
+	// Check Options
+	linesOfContext := uint32(defaultLinesOfContext)
+	if p.LinesOfContext != nil {
+		linesOfContext = *p.LinesOfContext
+	}
+	if linesOfContext > maxLinesOfContext {
+		return sr, nil, fmt.Sprintf("Lines of context must be between 0 and %d.", maxLinesOfContext)
+	}
+
+	if p.FileRegexp != "" {
+		if _, err := regexp.Compile(p.FileRegexp); err != nil {
+			return sr, nil, "Please provide a valid file regexp."
+		}
+	}
+
+	mode := p.Mode
+	if mode == "" {
+		mode = index.ModeRegex
+	}
+	if !index.ValidMode(mode) {
+		return sr, nil, "Please provide a valid mode (regex, literal, word or query)."
+	}
+
+	switch p.Scope {
+	case "", index.ScopeFile, index.ScopeExtension:
+		break
+	default:
+		return sr, nil, "Please provide a valid scope (file or extension)."
+	}
+
+	if p.Filters != nil {
+		if err := p.Filters.Validate(); err != nil {
+			return sr, nil, fmt.Sprintf("Please provide valid filters: %s.", err)
+		}
+	}
+
+	// Ensure the trigram index can narrow down the files to search
+	analysis, err := index.AnalyzeInput(p.Input, mode, p.IgnoreCase)
+	if err != nil {
+		return sr, nil, invalidInputMessage(mode, err)
+	}
+	if analysis.Unfiltered() {
+		return sr, analysis, "This search would read every file, please include at least three literal characters in a row."
+	}
+
+	sr.Input = p.Input
+	sr.Repo = strings.Join(repos, ",")
+	if len(repos) > 1 {
+		sr.Repos = repos
+	}
+	sr.Opts = search.Options{
+		IgnoreCase:     p.IgnoreCase,
+		LinesOfContext: linesOfContext,
+		FileRegexp:     p.FileRegexp,
+		IgnoreComments: p.IgnoreComments,
+		Offset:         p.Offset,
+		Limit:          p.Limit,
+		Mode:           mode,
+		Scope:          p.Scope,
+		Filters:        p.Filters,
+		Version:        p.Version,
+	}
+
+	return sr, analysis, ""
+}
+
+// searchTargets holds the Repositories a Search is run over
+// It may be given as a single name, "all" or a list of names.
+type searchTargets []string
+
+func (t *searchTargets) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*t = searchTargets{name}
+		return nil
+	}
+
+	var names []string
+	if err := json.Unmarshal(b, &names); err != nil {
+		return err
+	}
+	*t = names
+	return nil
+}
+
+// repos returns the unique Repository names targeted, in the order of the
+// known names, and whether they are all known
+func (t searchTargets) repos(known []string) ([]string, bool) {
+	valid := make(map[string]bool, len(known))
+	for _, name := range known {
+		valid[name] = true
+	}
+
+	selected := make(map[string]bool)
+	for _, name := range t {
+		switch {
+		case name == "all":
+			for _, name := range known {
+				selected[name] = true
+			}
+		case valid[name]:
+			selected[name] = true
+		default:
+			return nil, false
+		}
+	}
+
+	var repos []string
+	for _, name := range known {
+		if selected[name] {
+			repos = append(repos, name)
+		}
+	}
+	return repos, len(repos) > 0
+}
+
+// invalidInputMessage explains why search input could not be parsed
+func invalidInputMessage(mode string, err error) string {
+	if mode == index.ModeQuery && err != nil {
+		return fmt.Sprintf("Please provide a valid query: %s.", err)
+	}
+	return "Please provide a valid regular expression."
+}
+
+// analyzeSearch shows how the index will filter files for a search
+// Lets users check why a search would be rejected or slow before creating it.
+func (s *Server) analyzeSearch() http.HandlerFunc {
+	type analyzeSearchRequest struct {
+		Input      string `json:"input"`
+		IgnoreCase bool   `json:"ignore_case"`
+		Mode       string `json:"mode"`
+	}
+
+	type analyzeSearchResponse struct {
+		Query      string `json:"query"`
+		Trigrams   int    `json:"trigrams"`
+		Unfiltered bool   `json:"unfiltered"`
+		Weak       bool   `json:"weak"`
+		NoMatch    bool   `json:"no_match"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+
+		var data analyzeSearchRequest
+		err := decoder.Decode(&data)
+		if err != nil {
+			var resp errResponse
+			resp.Err = "Could not decode the POST body"
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		if !index.ValidMode(data.Mode) {
+			var resp errResponse
+			resp.Err = "Please provide a valid mode (regex, literal, word or query)."
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		analysis, err := index.AnalyzeInput(data.Input, data.Mode, data.IgnoreCase)
+		if data.Input == "" || err != nil {
+			var resp errResponse
+			resp.Err = invalidInputMessage(data.Mode, err)
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		var resp analyzeSearchResponse
+		resp.Query = analysis.Query
+		resp.Trigrams = analysis.Trigrams
+		resp.Unfiltered = analysis.Unfiltered()
+		resp.Weak = analysis.Weak()
+		resp.NoMatch = analysis.NoMatch
+
+		writeResp(w, resp)
+	}
+}
+
+// cancelSearch stops a queued or running Search
+// Only the creator of the Search (using the token returned when it was
+// created) or an admin user may cancel it.
+func (s *Server) cancelSearch() http.HandlerFunc {
+	type cancelSearchResponse struct {
+		ID     string `json:"id"`
+		Cancel bool   `json:"cancelled"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		searchID := chi.URLParam(r, "id")
+		if searchID == "" {
+			var resp errResponse
+			resp.Err = "You must specify a valid Search ID."
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		// Finished Searches are not in memory, Cancel reports why
+		if s.Manager.Exists(searchID) && !s.Manager.IsCreator(searchID, bearerToken(r)) && !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only the creator of a search or an admin may cancel it."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		err := s.Manager.Cancel(searchID)
+		switch err {
+		case nil:
+		case search.ErrSearchNotFound:
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Search %s not found", searchID)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		case search.ErrSearchFinished:
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Search %s has already finished", searchID)
+			w.WriteHeader(http.StatusConflict)
+			writeResp(w, resp)
+			return
+		default:
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Search %s could not be cancelled", searchID)
+			w.WriteHeader(http.StatusInternalServerError)
+			writeResp(w, resp)
+			return
+		}
+
+		var resp cancelSearchResponse
+		resp.ID = searchID
+		resp.Cancel = true
 		writeResp(w, resp)
 	}
 }
@@ -418,6 +956,7 @@ func (s *Server) createSearch() http.HandlerFunc {
 func (s *Server) getRepo() http.HandlerFunc {
 	type getRepoResponse struct {
 		Name            string `json:"name"`
+		Type            string `json:"type,omitempty"`
 		Total           int    `json:"total"`
 		PendingUpdates  int    `json:"pending_updates"`
 		CurrentRevision int    `json:"current_revision"`
@@ -429,18 +968,13 @@ func (s *Server) getRepo() http.HandlerFunc {
 		var resp getRepoResponse
 
 		if repoName := chi.URLParam(r, "name"); repoName != "" {
-			switch repoName {
-			case "plugins":
+			if rp := s.Manager.Repo(repoName); rp != nil {
 				resp.Name = repoName
-				resp.Total = int(s.Manager.Plugins.Len())
-				resp.PendingUpdates = len(s.Manager.Plugins.UpdateQueue)
-				resp.CurrentRevision = s.Manager.Plugins.GetRev()
-			case "themes":
-				resp.Name = repoName
-				resp.Total = int(s.Manager.Themes.Len())
-				resp.PendingUpdates = len(s.Manager.Themes.UpdateQueue)
-				resp.CurrentRevision = s.Manager.Themes.GetRev()
-			default:
+				resp.Type = rp.ExtType
+				resp.Total = int(rp.Len())
+				resp.PendingUpdates = rp.PendingUpdates()
+				resp.CurrentRevision = rp.GetRev()
+			} else {
 				resp.Err = "Repository Not Found."
 			}
 		} else {
@@ -452,65 +986,79 @@ func (s *Server) getRepo() http.HandlerFunc {
 }
 
 // getRepoOverview returns an overview of the Repositories
+// Plugins and Themes are the WordPress.org Repositories, if they are
+// registered, Repos lists every Repository.
 func (s *Server) getRepoOverview() http.HandlerFunc {
 	type getRepoOverviewResponse struct {
-		Plugins             repo.Repo `json:"plugins,omitempty"`
-		Themes              repo.Repo `json:"themes,omitempty"`
-		PluginChartInstalls string    `json:"plugin_chart_installs,omitempty"`
-		ThemeChartInstalls  string    `json:"theme_chart_installs,omitempty"`
-		PluginChartSize     string    `json:"plugin_chart_size,omitempty"`
-		ThemeChartSize      string    `json:"theme_chart_size,omitempty"`
-		UpdateQueue         int       `json:"update_queue,omitempty"`
+		Repos               []*repo.Repo `json:"repos"`
+		Plugins             *repo.Repo   `json:"plugins,omitempty"`
+		Themes              *repo.Repo   `json:"themes,omitempty"`
+		PluginChartInstalls string       `json:"plugin_chart_installs,omitempty"`
+		ThemeChartInstalls  string       `json:"theme_chart_installs,omitempty"`
+		PluginChartSize     string       `json:"plugin_chart_size,omitempty"`
+		ThemeChartSize      string       `json:"theme_chart_size,omitempty"`
+		UpdateQueue         int          `json:"update_queue,omitempty"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		var resp getRepoOverviewResponse
-		//s.Manager.Plugins.RLock()
-		resp.Plugins = *s.Manager.Plugins
-		//s.Manager.Plugins.RUnlock()
-
-		//s.Manager.Themes.RLock()
-		resp.Themes = *s.Manager.Themes
-		//s.Manager.Themes.RUnlock()
-
-		resp.PluginChartInstalls = s.Manager.Plugins.GetInstallsChart()
-		resp.PluginChartSize = s.Manager.Plugins.GetSizeChart()
+		resp.Repos = s.Manager.Repos()
+		resp.UpdateQueue = repo.PendingUpdates()
 
-		resp.ThemeChartInstalls = s.Manager.Themes.GetInstallsChart()
-		resp.ThemeChartSize = s.Manager.Themes.GetSizeChart()
+		if rp := s.Manager.Repo("plugins"); rp != nil {
+			resp.Plugins = rp
+			resp.PluginChartInstalls = rp.GetInstallsChart()
+			resp.PluginChartSize = rp.GetSizeChart()
+		}
 
-		resp.UpdateQueue = len(s.Manager.Plugins.UpdateQueue)
+		if rp := s.Manager.Repo("themes"); rp != nil {
+			resp.Themes = rp
+			resp.ThemeChartInstalls = rp.GetInstallsChart()
+			resp.ThemeChartSize = rp.GetSizeChart()
+		}
 
 		writeResp(w, resp)
 	}
 }
 
-// TODO: Combine the below into a single getExtension handler/endpoint
+// getExtension returns data for an Extension in any Repository
+func (s *Server) getExtension() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.writeExtension(w, chi.URLParam(r, "name"), chi.URLParam(r, "slug"))
+	}
+}
 
 // getPlugin returns data for a Plugin Extension
 func (s *Server) getPlugin() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if slug := chi.URLParam(r, "slug"); slug != "" {
-			p := s.Manager.Plugins.Get(slug)
-			writeResp(w, p)
-		} else {
-			var resp errResponse
-			resp.Err = "You must specify a valid Plugin Name"
-			writeResp(w, resp)
-		}
+		s.writeExtension(w, "plugins", chi.URLParam(r, "slug"))
 	}
 }
 
 // getTheme returns data for a Theme Extension
 func (s *Server) getTheme() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if slug := chi.URLParam(r, "slug"); slug != "" {
-			t := s.Manager.Themes.Get(slug)
-			writeResp(w, t)
-		} else {
-			var resp errResponse
-			resp.Err = "You must specify a valid Theme Name"
-			writeResp(w, resp)
-		}
+		s.writeExtension(w, "themes", chi.URLParam(r, "slug"))
 	}
 }
+
+// writeExtension writes the data of an Extension in the named Repository
+func (s *Server) writeExtension(w http.ResponseWriter, repoName, slug string) {
+	if slug == "" {
+		var resp errResponse
+		resp.Err = "You must specify a valid Extension Name"
+		writeResp(w, resp)
+		return
+	}
+
+	rp := s.Manager.Repo(repoName)
+	if rp == nil {
+		var resp errResponse
+		resp.Err = "Repository Not Found."
+		w.WriteHeader(http.StatusNotFound)
+		writeResp(w, resp)
+		return
+	}
+
+	writeResp(w, rp.Get(slug))
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/wpdirectory/wpdir/internal/index"
+	"github.com/wpdirectory/wpdir/internal/search"
+)
+
+func TestSearchTargetsUnmarshal(t *testing.T) {
+	tests := []struct {
+		json    string
+		targets searchTargets
+		valid   bool
+	}{
+		{`"plugins"`, searchTargets{"plugins"}, true},
+		{`"all"`, searchTargets{"all"}, true},
+		{`["themes","internal"]`, searchTargets{"themes", "internal"}, true},
+		{`42`, nil, false},
+	}
+
+	for _, test := range tests {
+		var targets searchTargets
+		err := json.Unmarshal([]byte(test.json), &targets)
+		if (err == nil) != test.valid {
+			t.Errorf("Expected valid %t got error %v for %s", test.valid, err, test.json)
+			continue
+		}
+		if test.valid && !reflect.DeepEqual(targets, test.targets) {
+			t.Errorf("Expected %+v got %+v for %s", test.targets, targets, test.json)
+		}
+	}
+}
+
+func TestSearchTargetsRepos(t *testing.T) {
+	known := []string{"plugins", "themes", "internal"}
+
+	tests := []struct {
+		targets searchTargets
+		repos   []string
+		ok      bool
+	}{
+		{searchTargets{"internal"}, []string{"internal"}, true},
+		{searchTargets{"all"}, []string{"plugins", "themes", "internal"}, true},
+		{searchTargets{"internal", "plugins"}, []string{"plugins", "internal"}, true},
+		{searchTargets{"themes", "all"}, []string{"plugins", "themes", "internal"}, true},
+		{searchTargets{"internal", "internal"}, []string{"internal"}, true},
+		{searchTargets{"internal", "missing"}, nil, false},
+		{searchTargets{"Internal"}, nil, false},
+		{searchTargets{}, nil, false},
+	}
+
+	for _, test := range tests {
+		repos, ok := test.targets.repos(known)
+		if ok != test.ok || !reflect.DeepEqual(repos, test.repos) {
+			t.Errorf("Expected %+v %t got %+v %t for %+v", test.repos, test.ok, repos, ok, test.targets)
+		}
+	}
+}
+
+func TestSearchRequest(t *testing.T) {
+	known := []string{"plugins", "themes"}
+	lines := func(n uint32) *uint32 { return &n }
+
+	tests := []struct {
+		params searchParams
+		opts   search.Options
+		msg    string
+	}{
+		{
+			searchParams{Input: `eval\(`, Target: searchTargets{"plugins"}},
+			search.Options{LinesOfContext: defaultLinesOfContext, Mode: index.ModeRegex},
+			"",
+		},
+		{
+			searchParams{Input: "eval(", Target: searchTargets{"plugins"}, IgnoreCase: true, LinesOfContext: lines(0), FileRegexp: `\.php$`, Offset: 20, Limit: 5, Mode: index.ModeLiteral},
+			search.Options{IgnoreCase: true, LinesOfContext: 0, FileRegexp: `\.php$`, Offset: 20, Limit: 5, Mode: index.ModeLiteral},
+			"",
+		},
+		{
+			searchParams{Input: "eval(", Target: searchTargets{"plugins"}, LinesOfContext: lines(maxLinesOfContext), Mode: index.ModeLiteral},
+			search.Options{LinesOfContext: maxLinesOfContext, Mode: index.ModeLiteral},
+			"",
+		},
+		{
+			searchParams{Input: "eval(", Target: searchTargets{"plugins"}, LinesOfContext: lines(maxLinesOfContext + 1), Mode: index.ModeLiteral},
+			search.Options{},
+			"Lines of context must be between 0 and 10.",
+		},
+		{
+			searchParams{Input: "eval(", Target: searchTargets{"plugins"}, FileRegexp: "(", Mode: index.ModeLiteral},
+			search.Options{},
+			"Please provide a valid file regexp.",
+		},
+		{
+			searchParams{Input: "", Target: searchTargets{"plugins"}},
+			search.Options{},
+			"Please provide non-blank search input.",
+		},
+		{
+			searchParams{Input: "eval(", Target: searchTargets{"missing"}},
+			search.Options{},
+			"Please provide a valid target",
+		},
+	}
+
+	for _, test := range tests {
+		sr, _, msg := test.params.searchRequest(known)
+		if msg != test.msg {
+			t.Errorf("Expected %q got %q for %+v", test.msg, msg, test.params)
+			continue
+		}
+		if msg != "" {
+			continue
+		}
+		if !reflect.DeepEqual(sr.Opts, test.opts) {
+			t.Errorf("Expected %+v got %+v for %+v", test.opts, sr.Opts, test.params)
+		}
+	}
+}
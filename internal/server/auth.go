@@ -0,0 +1,39 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// isAdmin checks the request has Basic Auth credentials matching
+// one of the users defined in the config
+func (s *Server) isAdmin(r *http.Request) bool {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	for _, u := range s.Config.Users {
+		if u.Username == "" || u.Password == "" {
+			continue
+		}
+		userMatch := subtle.ConstantTimeCompare([]byte(username), []byte(u.Username)) == 1
+		passMatch := subtle.ConstantTimeCompare([]byte(password), []byte(u.Password)) == 1
+		if userMatch && passMatch {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bearerToken returns the token from a Bearer Authorization header
+func bearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if len(auth) < len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(auth[len(prefix):])
+}
@@ -0,0 +1,310 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+
+	"github.com/go-chi/chi"
+	"github.com/wpdirectory/wpdir/internal/filestats"
+	"github.com/wpdirectory/wpdir/internal/index"
+	"github.com/wpdirectory/wpdir/internal/repo"
+)
+
+// diffDownload selects a freshly downloaded archive of the latest stable
+// release, instead of an indexed version
+const diffDownload = "download"
+
+var (
+	errVersionNotIndexed = errors.New("version not indexed")
+	errNoArchive         = errors.New("archive not available")
+)
+
+// getExtensionDiff returns the files changed between two versions of an
+// Extension. Versions may be any indexed version, latest or download, which
+// only admin users may use. The diff is returned as JSON, or as a patch
+// when format=patch is given.
+func (s *Server) getExtensionDiff() http.HandlerFunc {
+	type getExtensionDiffResponse struct {
+		Repo     string            `json:"repo"`
+		Slug     string            `json:"slug"`
+		From     string            `json:"from"`
+		To       string            `json:"to"`
+		Added    int               `json:"added"`
+		Removed  int               `json:"removed"`
+		Modified int               `json:"modified"`
+		Files    []*index.FileDiff `json:"files"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		repoName := chi.URLParam(r, "repo")
+		slug := chi.URLParam(r, "slug")
+
+		rp := s.Manager.Repo(repoName)
+		if rp == nil || !rp.Exists(slug) {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Extension %s/%s not found", repoName, slug)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		}
+		e := rp.Get(slug)
+
+		query := r.URL.Query()
+		from := query.Get("from")
+		to := query.Get("to")
+		if to == "" {
+			to = repo.VersionLatest
+		}
+		if from == "" || from == repo.VersionAll || to == repo.VersionAll {
+			var resp errResponse
+			resp.Err = "Please provide from and to versions (a version, latest or download)."
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		// Downloads fetch from upstream on every request
+		if (from == diffDownload || to == diffDownload) && !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only an admin may compare against a downloaded archive."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		var resp getExtensionDiffResponse
+		resp.Repo = repoName
+		resp.Slug = slug
+
+		var trees [2]index.Tree
+		for i, version := range []string{from, to} {
+			tree, resolved, release, err := s.versionTree(rp, e, version)
+			if err != nil {
+				var resp errResponse
+				switch err {
+				case errVersionNotIndexed:
+					resp.Err = fmt.Sprintf("Version %s of %s is not indexed", version, slug)
+					w.WriteHeader(http.StatusNotFound)
+				case errNoArchive:
+					resp.Err = fmt.Sprintf("No archive is available for %s", slug)
+					w.WriteHeader(http.StatusNotFound)
+				default:
+					resp.Err = fmt.Sprintf("Could not read version %s of %s", version, slug)
+					w.WriteHeader(http.StatusBadGateway)
+				}
+				writeResp(w, resp)
+				return
+			}
+			defer release()
+			trees[i] = tree
+			if i == 0 {
+				resp.From = resolved
+			} else {
+				resp.To = resolved
+			}
+		}
+
+		files, err := index.Diff(trees[0], trees[1])
+		if err != nil {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Could not compare %s and %s of %s", from, to, slug)
+			w.WriteHeader(http.StatusInternalServerError)
+			writeResp(w, resp)
+			return
+		}
+
+		if query.Get("format") == "patch" {
+			w.Header().Set("Content-Type", "text/x-diff; charset=utf-8")
+			for _, fd := range files {
+				io.WriteString(w, fd.Diff)
+			}
+			return
+		}
+
+		for _, fd := range files {
+			switch fd.Status {
+			case index.FileAdded:
+				resp.Added++
+			case index.FileRemoved:
+				resp.Removed++
+			case index.FileModified:
+				resp.Modified++
+			}
+		}
+		resp.Files = files
+		if resp.Files == nil {
+			resp.Files = []*index.FileDiff{}
+		}
+
+		writeResp(w, resp)
+	}
+}
+
+// versionTree returns the files of a version of an Extension along with
+// the version number, when it is known. An indexed version is kept until
+// release is called, so it is not removed by an update during the diff.
+func (s *Server) versionTree(rp *repo.Repo, e *repo.Extension, version string) (index.Tree, string, func(), error) {
+	e.RLock()
+	slug := e.Slug
+	latest := e.Version
+	e.RUnlock()
+
+	if version == diffDownload {
+		archive, err := rp.DownloadArchive(slug)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if len(archive) == 0 {
+			return nil, "", nil, errNoArchive
+		}
+		tree, err := index.ZipTree(archive)
+		return tree, latest, func() {}, err
+	}
+
+	idx, ok := e.AcquireVersion(version)
+	if !ok {
+		return nil, "", nil, errVersionNotIndexed
+	}
+	if idx.Ref.Version != "" {
+		version = idx.Ref.Version
+	}
+	release := func() {
+		if err := idx.Release(); err != nil {
+			s.Logger.Printf("Could not remove index of %s: %s\n", slug, err)
+		}
+	}
+	return idx.Ref.Tree(), version, release, nil
+}
+
+// treeFile is a file of an indexed Extension
+type treeFile struct {
+	Path      string `json:"path"`
+	Extension string `json:"extension"`
+	// Size is only known for the latest version
+	Size   int64  `json:"size,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// getExtensionTree lists the files of an indexed Extension, along with the
+// files excluded from the index and why. An indexed version may be given,
+// otherwise the latest version is listed.
+func (s *Server) getExtensionTree() http.HandlerFunc {
+	type getExtensionTreeResponse struct {
+		Repo       string      `json:"repo"`
+		Slug       string      `json:"slug"`
+		Version    string      `json:"version"`
+		Ref        string      `json:"ref,omitempty"`
+		Commit     string      `json:"commit,omitempty"`
+		TotalFiles int         `json:"total_files"`
+		Files      []*treeFile `json:"files"`
+		Excluded   []*treeFile `json:"excluded"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		repoName := chi.URLParam(r, "repo")
+		slug := chi.URLParam(r, "slug")
+
+		rp := s.Manager.Repo(repoName)
+		if rp == nil || !rp.Exists(slug) {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Extension %s/%s not found", repoName, slug)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		}
+		e := rp.Get(slug)
+
+		version := r.URL.Query().Get("version")
+		files, ref, ok := e.VersionFiles(version)
+		if !ok {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Version %s of %s is not indexed", version, slug)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		}
+
+		// File sizes are only recorded for the latest version
+		var sizes map[string]int64
+		if latest, ok := e.VersionRef(repo.VersionLatest); ok && latest == ref {
+			e.RLock()
+			sizes = fileSizes(e.Stats)
+			e.RUnlock()
+		}
+
+		var resp getExtensionTreeResponse
+		resp.Repo = repoName
+		resp.Slug = slug
+		resp.Version = ref.Version
+		resp.Ref = ref.Ref
+		resp.Commit = ref.Commit
+
+		resp.Files = make([]*treeFile, 0, len(files))
+		for _, name := range files {
+			resp.Files = append(resp.Files, newTreeFile(name, sizes))
+		}
+
+		resp.Excluded = []*treeFile{}
+		excluded, err := ref.ExcludedFiles()
+		if err != nil {
+			s.Logger.Printf("Could not read excluded files of %s: %s\n", slug, err)
+		}
+		for _, ex := range excluded {
+			f := newTreeFile(ex.Filename, sizes)
+			f.Reason = ex.Reason
+			resp.Excluded = append(resp.Excluded, f)
+		}
+
+		sort.Slice(resp.Files, func(i, j int) bool { return resp.Files[i].Path < resp.Files[j].Path })
+		sort.Slice(resp.Excluded, func(i, j int) bool { return resp.Excluded[i].Path < resp.Excluded[j].Path })
+		resp.TotalFiles = len(resp.Files) + len(resp.Excluded)
+
+		writeResp(w, resp)
+	}
+}
+
+// newTreeFile describes a file, with its size if known
+func newTreeFile(name string, sizes map[string]int64) *treeFile {
+	f := &treeFile{
+		Path:      name,
+		Extension: path.Ext(name),
+		Size:      sizes[name],
+	}
+	if f.Size == 0 {
+		f.Size = sizes[path.Base(name)]
+	}
+	return f
+}
+
+// fileSizes maps file paths to their sizes
+// Stats recorded before paths were kept only have the file name, which is
+// used when it is unique.
+func fileSizes(stats *filestats.Stats) map[string]int64 {
+	sizes := make(map[string]int64)
+	if stats == nil {
+		return sizes
+	}
+
+	stats.RLock()
+	defer stats.RUnlock()
+
+	names := make(map[string]int)
+	for _, f := range stats.Files {
+		if f.Path != "" {
+			sizes[f.Path] = f.Size
+			continue
+		}
+		names[f.Name]++
+		sizes[f.Name] = f.Size
+	}
+	for name, n := range names {
+		if n > 1 {
+			delete(sizes, name)
+		}
+	}
+	return sizes
+}
@@ -10,57 +10,37 @@ import (
 )
 
 // getFilePath returns a safe PATH to an Extension file
-func (s *Server) getFilePath(repository, slug, file string) (string, error) {
+// An empty version uses the latest indexed version.
+func (s *Server) getFilePath(repository, slug, version, file string) (string, error) {
 	// Protect against directory traversal attacks
 	if containsDotDot(repository) || containsDotDot(slug) || containsDotDot(file) {
 		return "", errors.New("Paths must not include '..'")
 	}
 
-	switch repository {
-	case "plugins":
-		if !s.Manager.Plugins.Exists(slug) {
-			return "", errors.New("No matching plugin")
-		}
-		p := s.Manager.Plugins.Get(slug)
-		if p.Status != repo.Open {
-			return "", errors.New("Plugin is Closed")
-		}
-
-		p.RLock()
-		dir := p.Dir()
-		p.RUnlock()
-
-		path := filepath.Join(dir, "raw", file)
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			return "", errors.New("File not found")
-		}
-
-		return path, nil
-
-	case "themes":
-		if !s.Manager.Themes.Exists(slug) {
-			return "", errors.New("No matching theme")
-		}
-
-		t := s.Manager.Themes.Get(slug)
-		if t.Status != repo.Open {
-			return "", errors.New("Theme has no indexed files")
-		}
-
-		t.RLock()
-		dir := t.Dir()
-		t.RUnlock()
+	rp := s.Manager.Repo(repository)
+	if rp == nil {
+		return "", errors.New("No matching repository")
+	}
 
-		path := filepath.Join(dir, "raw", file)
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			return "", errors.New("File not found")
-		}
+	if !rp.Exists(slug) {
+		return "", errors.New("No matching extension")
+	}
+	e := rp.Get(slug)
+	if e.Status != repo.Open {
+		return "", errors.New("Extension has no indexed files")
+	}
 
-		return path, nil
+	dir, ok := e.VersionDir(version)
+	if !ok {
+		return "", errors.New("Version not indexed")
+	}
 
-	default:
-		return "", errors.New("No matching repository")
+	path := filepath.Join(dir, "raw", file)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", errors.New("File not found")
 	}
+
+	return path, nil
 }
 
 // Coped from Go's net/http package
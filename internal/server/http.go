@@ -3,6 +3,8 @@ package server
 import (
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -19,31 +21,35 @@ import (
 	"github.com/ulule/limiter/drivers/middleware/stdlib"
 	"github.com/wpdirectory/wpdir/internal/data"
 	"github.com/wpdirectory/wpdir/internal/limit"
+	"github.com/wpdirectory/wpdir/internal/search"
 )
 
+// requestTimeout sets a timeout value on the request context (ctx), that will
+// signal through ctx.Done() that the request has timed out and further
+// processing should be stopped. Exports and streams are not limited.
+const requestTimeout = 60 * time.Second
+
 func (s *Server) startUp() {
 	s.Router = chi.NewRouter()
 
 	// Middleware Stack
+	s.Router.Use(writeDeadlineMiddleware)
 	s.Router.Use(middleware.RequestID)
 	s.Router.Use(middleware.RealIP)
 	s.Router.Use(middleware.Logger)
 	s.Router.Use(middleware.Recoverer)
-	s.Router.Use(middleware.DefaultCompress)
-	s.Router.Use(middleware.RedirectSlashes)
 
-	// Metrics
+	// Metrics, added before compression so the response still implements
+	// http.Flusher for streaming
 	s.Router.Use(metricsMiddleware)
 
-	// Set a timeout value on the request context (ctx), that will signal
-	// through ctx.Done() that the request has timed out and further
-	// processing should be stopped.
-	s.Router.Use(middleware.Timeout(60 * time.Second))
+	s.Router.Use(middleware.DefaultCompress)
+	s.Router.Use(middleware.RedirectSlashes)
 
 	// TODO: Remove this for prod?
 	cors := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST"},
+		AllowedMethods:   []string{"GET", "POST", "DELETE"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
@@ -51,8 +57,6 @@ func (s *Server) startUp() {
 	})
 	s.Router.Use(cors.Handler)
 
-	FileServer(s.Router, "/assets")
-
 	// Add root routes
 	s.routes()
 
@@ -124,25 +128,23 @@ func (s *Server) startHTTP() {
 	s.Router = chi.NewRouter()
 
 	// Middleware Stack
+	s.Router.Use(writeDeadlineMiddleware)
 	s.Router.Use(middleware.RequestID)
 	s.Router.Use(middleware.RealIP)
 	s.Router.Use(middleware.Logger)
 	s.Router.Use(middleware.Recoverer)
-	s.Router.Use(middleware.DefaultCompress)
-	s.Router.Use(middleware.RedirectSlashes)
 
-	// Metrics
+	// Metrics, added before compression so the response still implements
+	// http.Flusher for streaming
 	s.Router.Use(metricsMiddleware)
 
-	// Set a timeout value on the request context (ctx), that will signal
-	// through ctx.Done() that the request has timed out and further
-	// processing should be stopped.
-	s.Router.Use(middleware.Timeout(60 * time.Second))
+	s.Router.Use(middleware.DefaultCompress)
+	s.Router.Use(middleware.RedirectSlashes)
 
 	// TODO: Remove this for prod?
 	cors := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST"},
+		AllowedMethods:   []string{"GET", "POST", "DELETE"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
@@ -150,8 +152,6 @@ func (s *Server) startHTTP() {
 	})
 	s.Router.Use(cors.Handler)
 
-	FileServer(s.Router, "/assets")
-
 	s.routes()
 
 	cert := filepath.Join(s.Config.WD, "certs", "wpdirectory.net.crt")
@@ -220,19 +220,25 @@ func (s *Server) serveHTTPS(cert, key string) {
 }
 
 func (s *Server) routes() {
-	// Add Routes
-	s.Router.Get("/", s.static())
-	s.Router.Get("/search/{id}", s.static())
-	s.Router.Get("/searches", s.static())
-	s.Router.Get("/repos", s.static())
-	s.Router.Get("/about", s.static())
-	s.Router.Get("/metrics", promhttp.Handler().(http.HandlerFunc))
+	s.Router.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(requestTimeout))
+
+		FileServer(r, "/assets")
+
+		// Add Routes
+		r.Get("/", s.static())
+		r.Get("/search/{id}", s.static())
+		r.Get("/searches", s.static())
+		r.Get("/repos", s.static())
+		r.Get("/about", s.static())
+		r.Get("/metrics", promhttp.Handler().(http.HandlerFunc))
+	})
 
 	// Need to disable RedirectSlashes middleware to enable this
 	// redirects to /debug/prof/ which causes redirect loop
 	//s.Router.Mount("/debug", middleware.Profiler())
 
-	// Add API v1 routes
+	// Add API v1 routes, which set their own timeouts
 	s.Router.Mount("/api/v1", s.apiRoutes())
 
 	// Handle NotFound
@@ -242,27 +248,63 @@ func (s *Server) routes() {
 func (s *Server) apiRoutes() chi.Router {
 	r := chi.NewRouter()
 
+	// Exports and streams last as long as the export or Search, so they
+	// are left out of the request timeout
+	r.Get("/search/{id}/export", s.exportSearch())
+	r.Get("/search/stream/{id}", s.getSearchStream())
+
+	r.Group(s.timedAPIRoutes)
+
+	return r
+}
+
+// timedAPIRoutes adds the API routes which are cancelled once they run for
+// longer than requestTimeout
+func (s *Server) timedAPIRoutes(r chi.Router) {
+	r.Use(middleware.Timeout(requestTimeout))
+
 	middleware := stdlib.NewMiddleware(limit.New(), stdlib.WithForwardHeader(true))
 
 	r.Get("/loaded", s.getLoaded())
 	
 	r.Get("/search/{id}", s.getSearch())
 	r.Post("/search/new", middleware.Handler(s.createSearch()).(http.HandlerFunc))
+	r.Post("/search/analyze", s.analyzeSearch())
+	r.Delete("/search/{id}", s.cancelSearch())
 	r.Get("/searches/{limit}", s.getSearches())
 	r.Get("/search/matches/{id}/{slug}", s.getSearchMatches())
+	r.Get("/search/matches/{id}/{repo}/{slug}", s.getSearchMatches())
 
 	r.Get("/search/summary/{id}", s.getSearchSummary())
 
+	r.Get("/watches", s.getWatches())
+	r.Post("/watch/new", s.createWatch())
+	r.Get("/watch/{id}", s.getWatch())
+	r.Get("/watch/{id}/changes", s.getWatchChanges())
+	r.Get("/watch/{id}/events", s.getWatchEvents())
+	r.Delete("/watch/{id}", s.deleteWatch())
+
+	r.Get("/webhooks/deliveries", s.getDeliveries())
+
+	r.Get("/updates", s.getUpdates())
+	r.Post("/updates/pause", s.pauseUpdates(false))
+	r.Post("/updates/resume", s.pauseUpdates(true))
+	r.Post("/updates/{repo}/requeue-closed", s.requeueClosed())
+	r.Post("/updates/{repo}/jobs/{job}", s.startJob())
+	r.Post("/updates/{repo}/reindex/{slug}", s.reindexExtension())
+
 	r.Post("/file", s.getMatchFile())
 
 	r.Get("/repo/{name}", s.getRepo())
+	r.Get("/repo/{name}/{slug}", s.getExtension())
 	r.Get("/repos/overview", s.getRepoOverview())
 
 	r.Get("/plugin/{slug}", s.getPlugin())
 
 	r.Get("/theme/{slug}", s.getTheme())
 
-	return r
+	r.Get("/{repo}/{slug}/diff", s.getExtensionDiff())
+	r.Get("/{repo}/{slug}/tree", s.getExtensionTree())
 }
 
 // FileServer conveniently sets up a http.FileServer handler to serve
@@ -299,6 +341,23 @@ func writeResp(w http.ResponseWriter, data interface{}) {
 	writeJSON(w, data, http.StatusOK)
 }
 
+func writeStreamHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+}
+
+func writeEvent(w io.Writer, id int, e *search.Event) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("Failed to encode event: %v\n", err)
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, e.Type, b)
+}
+
 func writeError(w http.ResponseWriter, err error, status int) {
 	writeJSON(w, map[string]string{
 		"Error": err.Error(),
@@ -1,7 +1,10 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -13,3 +16,26 @@ func metricsMiddleware(h http.Handler) http.Handler {
 
 	return http.HandlerFunc(fn)
 }
+
+// writeDeadlineKey holds the func setting the write deadline of a request
+type writeDeadlineKey struct{}
+
+// writeDeadlineMiddleware lets handlers extend the server WriteTimeout
+// The compress and metrics middleware wrap the ResponseWriter without
+// unwrapping, so it must be added before them.
+func writeDeadlineMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+		ctx := context.WithValue(r.Context(), writeDeadlineKey{}, rc.SetWriteDeadline)
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// extendWriteDeadline allows the response to a request to be written for d
+func extendWriteDeadline(r *http.Request, d time.Duration) error {
+	set, ok := r.Context().Value(writeDeadlineKey{}).(func(time.Time) error)
+	if !ok {
+		return errors.New("Write deadline cannot be set")
+	}
+	return set(time.Now().Add(d))
+}
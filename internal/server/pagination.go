@@ -0,0 +1,85 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultPerPage is used when only the page is requested
+	defaultPerPage = 100
+	// maxPerPage is the most items returned in a single page
+	maxPerPage = 1000
+)
+
+// pageParams reads the page and per_page query parameters and reports
+// whether they are valid. perPage is zero when neither is given, meaning
+// all items are returned.
+func pageParams(query url.Values) (page, perPage int, ok bool) {
+	if query.Get("page") == "" && query.Get("per_page") == "" {
+		return 1, 0, true
+	}
+
+	var err error
+	page, perPage = 1, defaultPerPage
+	if v := query.Get("page"); v != "" {
+		if page, err = strconv.Atoi(v); err != nil || page < 1 {
+			return 0, 0, false
+		}
+	}
+	if v := query.Get("per_page"); v != "" {
+		if perPage, err = strconv.Atoi(v); err != nil || perPage < 1 || perPage > maxPerPage {
+			return 0, 0, false
+		}
+	}
+
+	return page, perPage, true
+}
+
+// pageBounds returns the range of items shown on a page and the number of
+// pages needed to show total items
+func pageBounds(total, page, perPage int) (start, end, pages int) {
+	pages = (total + perPage - 1) / perPage
+
+	start = (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end = start + perPage
+	if end > total {
+		end = total
+	}
+	return start, end, pages
+}
+
+// linkHeader returns a Link header pointing to the first, previous, next
+// and last pages, keeping the other query parameters of the request
+func linkHeader(u *url.URL, page, pages int) string {
+	if pages < 1 {
+		return ""
+	}
+
+	link := func(p int, rel string) string {
+		query := u.Query()
+		query.Set("page", strconv.Itoa(p))
+		ref := url.URL{Path: u.Path, RawQuery: query.Encode()}
+		return fmt.Sprintf("<%s>; rel=\"%s\"", ref.String(), rel)
+	}
+
+	links := []string{link(1, "first")}
+	if page > 1 {
+		prev := page - 1
+		if prev > pages {
+			prev = pages
+		}
+		links = append(links, link(prev, "prev"))
+	}
+	if page < pages {
+		links = append(links, link(page+1, "next"))
+	}
+	links = append(links, link(pages, "last"))
+
+	return strings.Join(links, ", ")
+}
@@ -24,19 +24,20 @@ type Server struct {
 // New returns a pointer to the main server struct
 func New(log *log.Logger, config *config.Config, fresh *bool) *Server {
 
+	sm := search.NewManager(config.SearchWorkers, config.ConcurrentSearches, config.SearchTimeout, config.MatchLineLength)
+
 	// Init Repos
-	pr := repo.New(config, log, "plugins", 0)
-	tr := repo.New(config, log, "themes", 0)
+	for _, rc := range config.Repositories {
+		r := repo.New(config, log, rc, 0)
 
-	// TODO: Auto generate in background
-	//pr.GenerateInstallsChart()
-	//tr.GenerateInstallsChart()
-	//pr.GenerateSizeChart()
-	//tr.GenerateSizeChart()
+		// TODO: Auto generate in background
+		//r.GenerateInstallsChart()
+		//r.GenerateSizeChart()
 
-	sm := search.NewManager(config.SearchWorkers)
-	sm.Plugins = pr
-	sm.Themes = tr
+		// Check Watches against Extensions as they are updated
+		r.SetUpdateHook(sm.CheckUpdate)
+		sm.AddRepo(r)
+	}
 
 	// Debug Delete Searches
 	// Need to reset after break code changes
@@ -56,26 +57,27 @@ func New(log *log.Logger, config *config.Config, fresh *bool) *Server {
 
 // LoadData loads all existing DB and Index data
 func (s *Server) LoadData(fresh *bool) {
+	repos := s.Manager.Repos()
+
 	// Load Existing from DB
-	s.Manager.Plugins.LoadExisting()
-	s.Manager.Themes.LoadExisting()
+	for _, r := range repos {
+		r.LoadExisting()
+	}
 
 	// Initial List
-	err := s.Manager.Plugins.UpdateList(fresh)
-	if err != nil {
-		s.Logger.Fatalf("Could not get initial plugin list")
-	}
-	err = s.Manager.Themes.UpdateList(fresh)
-	if err != nil {
-		s.Logger.Fatalf("Could not get initial theme list")
+	for _, r := range repos {
+		err := r.UpdateList(fresh)
+		if err != nil {
+			s.Logger.Fatalf("Could not get initial %s list: %s", r.Name, err)
+		}
 	}
 
 	// Start Update Workers
 	// These process updates from the queue
-	repo.StartUpdateWorkers(s.Config.UpdateWorkers, s.Manager.Plugins, s.Manager.Themes)
+	repo.StartUpdateWorkers(s.Config.UpdateWorkers, repos)
 
-	// Start Worker to Process Searches
-	go s.Manager.Worker()
+	// Start Workers to Process Searches
+	s.Manager.StartWorkers()
 
 	s.Manager.Lock()
 	s.Manager.Loaded = true
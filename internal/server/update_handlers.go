@@ -0,0 +1,296 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/wpdirectory/wpdir/internal/repo"
+	"github.com/wpdirectory/wpdir/internal/repo/queue"
+)
+
+// getUpdates returns the queued, in flight and failed updates, optionally
+// filtered by Repo and state. Only admin users may view them.
+func (s *Server) getUpdates() http.HandlerFunc {
+	type getUpdatesResponse struct {
+		Paused  bool            `json:"paused"`
+		Counts  map[string]int  `json:"counts"`
+		Updates []*queue.Status `json:"updates"`
+		Total   int             `json:"total"`
+		Page    int             `json:"page,omitempty"`
+		PerPage int             `json:"per_page,omitempty"`
+		Pages   int             `json:"pages,omitempty"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only an admin may view updates."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		query := r.URL.Query()
+		page, perPage, ok := pageParams(query)
+		if !ok {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Please provide a valid page and a per_page between 1 and %d.", maxPerPage)
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		repoName := query.Get("repo")
+		if repoName != "" && s.Manager.Repo(repoName) == nil {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Repository %s not found", repoName)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		}
+
+		state := query.Get("state")
+		switch state {
+		case "", queue.StateReady, queue.StateInFlight, queue.StateDelayed, queue.StateDead:
+		default:
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Please provide a state of %s, %s, %s or %s.", queue.StateReady, queue.StateInFlight, queue.StateDelayed, queue.StateDead)
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		dead, err := repo.DeadUpdates()
+		if err != nil {
+			var resp errResponse
+			resp.Err = "Could not load failed updates."
+			w.WriteHeader(http.StatusInternalServerError)
+			writeResp(w, resp)
+			return
+		}
+
+		all := repo.UpdateItems()
+		for _, it := range dead {
+			all = append(all, &queue.Status{Item: *it, State: queue.StateDead})
+		}
+
+		var resp getUpdatesResponse
+		resp.Paused = repo.UpdatesPaused()
+		resp.Counts = map[string]int{
+			queue.StateReady:    0,
+			queue.StateInFlight: 0,
+			queue.StateDelayed:  0,
+			queue.StateDead:     0,
+		}
+
+		updates := []*queue.Status{}
+		for _, it := range all {
+			if repoName != "" && it.Repo != repoName {
+				continue
+			}
+			resp.Counts[it.State]++
+			if state != "" && it.State != state {
+				continue
+			}
+			updates = append(updates, it)
+		}
+
+		resp.Total = len(updates)
+		resp.Updates = updates
+
+		if perPage > 0 {
+			start, end, pages := pageBounds(len(updates), page, perPage)
+			resp.Page = page
+			resp.PerPage = perPage
+			resp.Pages = pages
+			resp.Updates = updates[start:end]
+
+			if link := linkHeader(r.URL, page, resp.Pages); link != "" {
+				w.Header().Set("Link", link)
+			}
+		}
+
+		writeResp(w, resp)
+	}
+}
+
+// pauseUpdates stops the update workers starting new updates, or lets them
+// start again when resume is set. Only admin users may pause updates.
+func (s *Server) pauseUpdates(resume bool) http.HandlerFunc {
+	type pauseUpdatesResponse struct {
+		Paused bool `json:"paused"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only an admin may pause or resume updates."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		if resume {
+			repo.ResumeUpdates()
+		} else {
+			repo.PauseUpdates()
+		}
+
+		var resp pauseUpdatesResponse
+		resp.Paused = repo.UpdatesPaused()
+		writeResp(w, resp)
+	}
+}
+
+// reindexExtension queues an update of an Extension, even if it is already
+// queued or failed. Only admin users may force updates.
+func (s *Server) reindexExtension() http.HandlerFunc {
+	type reindexExtensionResponse struct {
+		Repo   string `json:"repo"`
+		Slug   string `json:"slug"`
+		Queued bool   `json:"queued"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only an admin may re-index extensions."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		repoName := chi.URLParam(r, "repo")
+		slug := chi.URLParam(r, "slug")
+
+		rp := s.Manager.Repo(repoName)
+		if rp == nil || !rp.Exists(slug) {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Extension %s not found in %s", slug, repoName)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		}
+
+		if err := rp.Reindex(slug); err != nil {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Could not queue %s for re-indexing.", slug)
+			w.WriteHeader(http.StatusInternalServerError)
+			writeResp(w, resp)
+			return
+		}
+
+		var resp reindexExtensionResponse
+		resp.Repo = repoName
+		resp.Slug = slug
+		resp.Queued = true
+		w.WriteHeader(http.StatusAccepted)
+		writeResp(w, resp)
+	}
+}
+
+// requeueClosed queues updates of every Closed Extension in a Repo
+// Only admin users may requeue them.
+func (s *Server) requeueClosed() http.HandlerFunc {
+	type requeueClosedResponse struct {
+		Repo   string `json:"repo"`
+		Queued int    `json:"queued"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only an admin may requeue closed extensions."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		repoName := chi.URLParam(r, "repo")
+		rp := s.Manager.Repo(repoName)
+		if rp == nil {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Repository %s not found", repoName)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		}
+
+		queued, err := rp.RequeueClosed()
+		if err != nil {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Could not requeue closed extensions in %s.", repoName)
+			w.WriteHeader(http.StatusInternalServerError)
+			writeResp(w, resp)
+			return
+		}
+
+		var resp requeueClosedResponse
+		resp.Repo = repoName
+		resp.Queued = queued
+		w.WriteHeader(http.StatusAccepted)
+		writeResp(w, resp)
+	}
+}
+
+// startJob runs a Repo job, checking the changelog or updating meta data,
+// in the background. Only admin users may start jobs.
+func (s *Server) startJob() http.HandlerFunc {
+	type startJobResponse struct {
+		Repo    string `json:"repo"`
+		Job     string `json:"job"`
+		Started bool   `json:"started"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only an admin may start jobs."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		repoName := chi.URLParam(r, "repo")
+		job := chi.URLParam(r, "job")
+
+		rp := s.Manager.Repo(repoName)
+		if rp == nil {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Repository %s not found", repoName)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		}
+
+		switch err := rp.StartJob(job); err {
+		case nil:
+		case repo.ErrUnknownJob:
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Please provide a job of %s or %s.", repo.JobCheckChangelog, repo.JobUpdateMeta)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		case repo.ErrJobRunning:
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Job %s is already running for %s", job, repoName)
+			w.WriteHeader(http.StatusConflict)
+			writeResp(w, resp)
+			return
+		default:
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Job %s could not be started", job)
+			w.WriteHeader(http.StatusInternalServerError)
+			writeResp(w, resp)
+			return
+		}
+
+		var resp startJobResponse
+		resp.Repo = repoName
+		resp.Job = job
+		resp.Started = true
+		w.WriteHeader(http.StatusAccepted)
+		writeResp(w, resp)
+	}
+}
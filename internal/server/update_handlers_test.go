@@ -0,0 +1,231 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wpdirectory/wpdir/internal/config"
+	"github.com/wpdirectory/wpdir/internal/repo"
+	"github.com/wpdirectory/wpdir/internal/repo/queue"
+	"github.com/wpdirectory/wpdir/internal/search"
+	"github.com/wpdirectory/wpdir/internal/source"
+)
+
+func init() {
+	repo.UseUpdateStore(queue.NewMemStore())
+}
+
+// testSource is a Source without Extensions, Revision is held until block
+// is closed when it is set
+type testSource struct {
+	started chan struct{}
+	block   chan struct{}
+}
+
+func (s *testSource) List() ([]string, error) { return nil, nil }
+
+func (s *testSource) Revision() (int, error) {
+	if s.block == nil {
+		return 1, nil
+	}
+	select {
+	case s.started <- struct{}{}:
+	default:
+	}
+	<-s.block
+	return 0, errors.New("Source closed")
+}
+
+func (s *testSource) Changes(from, to int) (map[string]int, error) { return nil, nil }
+func (s *testSource) Info(slug string) ([]byte, error)             { return nil, nil }
+func (s *testSource) Archive(slug string) ([]byte, error)          { return nil, nil }
+
+// testServer returns a Server with a Repo named after each of the names
+// reading from src, the Extensions listed are added to the first
+func testServer(t *testing.T, src source.Source, slugs []string, names ...string) *Server {
+	cfg := &config.Config{
+		Name:    "wpdir",
+		Version: "test",
+		Users:   []config.User{{Username: "admin", Password: "secret"}},
+	}
+	l := log.New(ioutil.Discard, "", 0)
+
+	sm := search.NewManager(1, 1, 0, 0)
+	for _, name := range names {
+		rc := config.Repository{Name: name, Type: "plugins"}
+		sm.AddRepo(repo.NewWithSource(cfg, l, rc, src, 0))
+	}
+	for _, slug := range slugs {
+		sm.Repos()[0].Add(slug)
+	}
+
+	return &Server{
+		Config:  cfg,
+		Logger:  l,
+		Manager: sm,
+	}
+}
+
+// request makes a request to the API, as an admin if admin is set
+func request(s *Server, method, target string, admin bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if admin {
+		req.SetBasicAuth("admin", "secret")
+	}
+	rec := httptest.NewRecorder()
+	s.apiRoutes().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestUpdatesAdmin(t *testing.T) {
+	s := testServer(t, &testSource{}, []string{"hello"}, "admin-only")
+
+	tests := []struct {
+		method string
+		target string
+	}{
+		{http.MethodGet, "/updates"},
+		{http.MethodPost, "/updates/pause"},
+		{http.MethodPost, "/updates/resume"},
+		{http.MethodPost, "/updates/admin-only/requeue-closed"},
+		{http.MethodPost, "/updates/admin-only/jobs/check-changelog"},
+		{http.MethodPost, "/updates/admin-only/reindex/hello"},
+	}
+
+	for _, test := range tests {
+		rec := request(s, test.method, test.target, false)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected %d got %d for %s %s", http.StatusForbidden, rec.Code, test.method, test.target)
+		}
+	}
+}
+
+func TestUpdatesNotFound(t *testing.T) {
+	s := testServer(t, &testSource{}, []string{"hello"}, "not-found")
+
+	tests := []struct {
+		method string
+		target string
+	}{
+		{http.MethodGet, "/updates?repo=missing"},
+		{http.MethodPost, "/updates/missing/requeue-closed"},
+		{http.MethodPost, "/updates/missing/jobs/check-changelog"},
+		{http.MethodPost, "/updates/not-found/jobs/missing"},
+		{http.MethodPost, "/updates/missing/reindex/hello"},
+		{http.MethodPost, "/updates/not-found/reindex/missing"},
+	}
+
+	for _, test := range tests {
+		rec := request(s, test.method, test.target, true)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected %d got %d for %s %s", http.StatusNotFound, rec.Code, test.method, test.target)
+		}
+	}
+}
+
+func TestReindexExtension(t *testing.T) {
+	// An Extension named jobs must not be taken for a job
+	s := testServer(t, &testSource{}, []string{"hello", "jobs"}, "reindex")
+
+	for _, slug := range []string{"hello", "jobs"} {
+		rec := request(s, http.MethodPost, "/updates/reindex/reindex/"+slug, true)
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("Expected %d got %d for %s: %s", http.StatusAccepted, rec.Code, slug, rec.Body)
+		}
+
+		var resp struct {
+			Repo   string `json:"repo"`
+			Slug   string `json:"slug"`
+			Queued bool   `json:"queued"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Could not decode response: %s", err)
+		}
+		if resp.Repo != "reindex" || resp.Slug != slug || !resp.Queued {
+			t.Errorf("Unexpected response %+v for %s", resp, slug)
+		}
+	}
+}
+
+func TestGetUpdatesPages(t *testing.T) {
+	slugs := []string{"a", "b", "c", "d", "e"}
+	s := testServer(t, &testSource{}, slugs, "pages")
+
+	rec := request(s, http.MethodPost, "/updates/pages/requeue-closed", true)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected %d got %d: %s", http.StatusAccepted, rec.Code, rec.Body)
+	}
+
+	tests := []struct {
+		query   string
+		code    int
+		updates int
+		pages   int
+		link    bool
+	}{
+		{"", http.StatusOK, 5, 0, false},
+		{"&page=1&per_page=2", http.StatusOK, 2, 3, true},
+		{"&page=3&per_page=2", http.StatusOK, 1, 3, true},
+		{"&page=4&per_page=2", http.StatusOK, 0, 3, true},
+		{"&state=ready&per_page=10", http.StatusOK, 5, 1, true},
+		{"&state=dead", http.StatusOK, 0, 0, false},
+		{"&page=0", http.StatusBadRequest, 0, 0, false},
+		{"&per_page=1001", http.StatusBadRequest, 0, 0, false},
+		{"&state=unknown", http.StatusBadRequest, 0, 0, false},
+	}
+
+	for _, test := range tests {
+		rec := request(s, http.MethodGet, "/updates?repo=pages"+test.query, true)
+		if rec.Code != test.code {
+			t.Errorf("Expected %d got %d for %s", test.code, rec.Code, test.query)
+			continue
+		}
+		if test.code != http.StatusOK {
+			continue
+		}
+
+		var resp struct {
+			Total   int               `json:"total"`
+			Pages   int               `json:"pages"`
+			Counts  map[string]int    `json:"counts"`
+			Updates []json.RawMessage `json:"updates"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Could not decode response: %s", err)
+		}
+		if len(resp.Updates) != test.updates || resp.Pages != test.pages || resp.Counts["ready"] != len(slugs) {
+			t.Errorf("Expected %d updates and %d pages got %+v for %s", test.updates, test.pages, resp, test.query)
+		}
+		if link := rec.Header().Get("Link"); (link != "") != test.link {
+			t.Errorf("Expected a Link header %t got %q for %s", test.link, link, test.query)
+		}
+	}
+}
+
+func TestStartJobRunning(t *testing.T) {
+	// The Source holds the changelog check until the test is done
+	src := &testSource{started: make(chan struct{}, 1), block: make(chan struct{})}
+	defer close(src.block)
+
+	s := testServer(t, src, nil, "running")
+
+	target := "/updates/running/jobs/" + repo.JobCheckChangelog
+	if rec := request(s, http.MethodPost, target, true); rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected %d got %d: %s", http.StatusAccepted, rec.Code, rec.Body)
+	}
+	<-src.started
+
+	rec := request(s, http.MethodPost, target, true)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected %d got %d: %s", http.StatusConflict, rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), "already running") {
+		t.Errorf("Unexpected response %s", rec.Body)
+	}
+}
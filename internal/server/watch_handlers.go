@@ -0,0 +1,307 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/wpdirectory/wpdir/internal/search"
+)
+
+// getWatches returns every Watch, only admin users may list them
+func (s *Server) getWatches() http.HandlerFunc {
+	type getWatchesResponse struct {
+		Watches []*search.Watch `json:"watches"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only an admin may list watches."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		watches, err := s.Manager.Watches()
+		if err != nil {
+			var resp errResponse
+			resp.Err = "Could not load watches."
+			w.WriteHeader(http.StatusInternalServerError)
+			writeResp(w, resp)
+			return
+		}
+
+		var resp getWatchesResponse
+		resp.Watches = watches
+		writeResp(w, resp)
+	}
+}
+
+// createWatch saves a Search to be run again on an interval, given in
+// seconds. Only admin users may create Watches.
+func (s *Server) createWatch() http.HandlerFunc {
+	type createWatchRequest struct {
+		searchParams
+		Name     string `json:"name"`
+		Interval int64  `json:"interval"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only an admin may create watches."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		var data createWatchRequest
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil {
+			var resp errResponse
+			resp.Err = "Could not decode the POST body"
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		interval := time.Duration(data.Interval) * time.Second
+		if interval < search.MinWatchInterval {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Please provide an interval of at least %d seconds.", int(search.MinWatchInterval.Seconds()))
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		sr, _, msg := data.searchRequest(s.Manager.RepoNames())
+		if msg != "" {
+			var resp errResponse
+			resp.Err = msg
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		watch, err := s.Manager.NewWatch(data.Name, sr, interval)
+		if err != nil {
+			var resp errResponse
+			resp.Err = "Could not save the watch."
+			w.WriteHeader(http.StatusInternalServerError)
+			writeResp(w, resp)
+			return
+		}
+
+		writeResp(w, watch)
+	}
+}
+
+// getWatch returns a Watch and when it last ran, only admin users may view
+// it as Watch Searches are private
+func (s *Server) getWatch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only an admin may view watches."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		watchID := chi.URLParam(r, "id")
+
+		watch, err := s.Manager.GetWatch(watchID)
+		if err != nil {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Watch %s not found", watchID)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		}
+
+		writeResp(w, watch)
+	}
+}
+
+// getWatchChanges returns the Matches added and removed by each run of a
+// Watch, newest first. Runs are paginated when page or per_page are given.
+// Only admin users may view them.
+func (s *Server) getWatchChanges() http.HandlerFunc {
+	type getWatchChangesResponse struct {
+		Changes []*search.WatchChanges `json:"changes"`
+		Total   int                    `json:"total"`
+		Page    int                    `json:"page,omitempty"`
+		PerPage int                    `json:"per_page,omitempty"`
+		Pages   int                    `json:"pages,omitempty"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only an admin may view watch changes."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		watchID := chi.URLParam(r, "id")
+
+		page, perPage, ok := pageParams(r.URL.Query())
+		if !ok {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Please provide a valid page and a per_page between 1 and %d.", maxPerPage)
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		if _, err := s.Manager.GetWatch(watchID); err != nil {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Watch %s not found", watchID)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		}
+
+		changes, err := s.Manager.WatchChanges(watchID)
+		if err != nil {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Could not load changes for Watch %s", watchID)
+			w.WriteHeader(http.StatusInternalServerError)
+			writeResp(w, resp)
+			return
+		}
+
+		var resp getWatchChangesResponse
+		resp.Total = len(changes)
+		resp.Changes = changes
+
+		if perPage > 0 {
+			start, end, pages := pageBounds(len(changes), page, perPage)
+			resp.Page = page
+			resp.PerPage = perPage
+			resp.Pages = pages
+			resp.Changes = changes[start:end]
+
+			if link := linkHeader(r.URL, page, resp.Pages); link != "" {
+				w.Header().Set("Link", link)
+			}
+		}
+
+		writeResp(w, resp)
+	}
+}
+
+// getWatchEvents returns the new Matches of a Watch found as Extensions
+// were updated, newest first. Events are paginated when page or per_page
+// are given. Only admin users may view them.
+func (s *Server) getWatchEvents() http.HandlerFunc {
+	type getWatchEventsResponse struct {
+		Events  []*search.WatchEvent `json:"events"`
+		Total   int                  `json:"total"`
+		Page    int                  `json:"page,omitempty"`
+		PerPage int                  `json:"per_page,omitempty"`
+		Pages   int                  `json:"pages,omitempty"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only an admin may view watch events."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		watchID := chi.URLParam(r, "id")
+
+		page, perPage, ok := pageParams(r.URL.Query())
+		if !ok {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Please provide a valid page and a per_page between 1 and %d.", maxPerPage)
+			w.WriteHeader(http.StatusBadRequest)
+			writeResp(w, resp)
+			return
+		}
+
+		if _, err := s.Manager.GetWatch(watchID); err != nil {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Watch %s not found", watchID)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		}
+
+		events, err := s.Manager.WatchEvents(watchID)
+		if err != nil {
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Could not load events for Watch %s", watchID)
+			w.WriteHeader(http.StatusInternalServerError)
+			writeResp(w, resp)
+			return
+		}
+
+		var resp getWatchEventsResponse
+		resp.Total = len(events)
+		resp.Events = events
+
+		if perPage > 0 {
+			start, end, pages := pageBounds(len(events), page, perPage)
+			resp.Page = page
+			resp.PerPage = perPage
+			resp.Pages = pages
+			resp.Events = events[start:end]
+
+			if link := linkHeader(r.URL, page, resp.Pages); link != "" {
+				w.Header().Set("Link", link)
+			}
+		}
+
+		writeResp(w, resp)
+	}
+}
+
+// deleteWatch stops a Watch from running, only admin users may delete them
+func (s *Server) deleteWatch() http.HandlerFunc {
+	type deleteWatchResponse struct {
+		ID      string `json:"id"`
+		Deleted bool   `json:"deleted"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		watchID := chi.URLParam(r, "id")
+
+		if !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only an admin may delete watches."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		err := s.Manager.DeleteWatch(watchID)
+		switch err {
+		case nil:
+		case search.ErrWatchNotFound:
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Watch %s not found", watchID)
+			w.WriteHeader(http.StatusNotFound)
+			writeResp(w, resp)
+			return
+		default:
+			var resp errResponse
+			resp.Err = fmt.Sprintf("Watch %s could not be deleted", watchID)
+			w.WriteHeader(http.StatusInternalServerError)
+			writeResp(w, resp)
+			return
+		}
+
+		var resp deleteWatchResponse
+		resp.ID = watchID
+		resp.Deleted = true
+		writeResp(w, resp)
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/wpdirectory/wpdir/internal/webhook"
+)
+
+// getDeliveries returns the most recent webhook deliveries, up to the limit
+// given in the query. Only admin users may view them.
+func (s *Server) getDeliveries() http.HandlerFunc {
+	type getDeliveriesResponse struct {
+		Deliveries []*webhook.Delivery `json:"deliveries"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(r) {
+			var resp errResponse
+			resp.Err = "Only an admin may view webhook deliveries."
+			w.WriteHeader(http.StatusForbidden)
+			writeResp(w, resp)
+			return
+		}
+
+		limit := defaultPerPage
+		if v := r.URL.Query().Get("limit"); v != "" {
+			var err error
+			if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxPerPage {
+				var resp errResponse
+				resp.Err = fmt.Sprintf("Please provide a limit between 1 and %d.", maxPerPage)
+				w.WriteHeader(http.StatusBadRequest)
+				writeResp(w, resp)
+				return
+			}
+		}
+
+		deliveries, err := webhook.Deliveries(limit)
+		if err != nil {
+			var resp errResponse
+			resp.Err = "Could not load webhook deliveries."
+			w.WriteHeader(http.StatusInternalServerError)
+			writeResp(w, resp)
+			return
+		}
+
+		var resp getDeliveriesResponse
+		resp.Deliveries = deliveries
+		writeResp(w, resp)
+	}
+}
@@ -0,0 +1,199 @@
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	// slugPlaceholder is replaced by the slug in Git URLs
+	slugPlaceholder = "{slug}"
+	// defaultGitRef names the remote HEAD, which is used when no ref is set
+	defaultGitRef = "HEAD"
+)
+
+// Git clones Extensions from a Git repository each. As Git has no repository
+// wide revision, revisions count the checks which found a ref had moved and
+// an Extension has changed when the commit its ref points to differs from
+// the one last indexed.
+type Git struct {
+	url   string
+	ref   string
+	slugs []string
+	rev   int
+	// commits holds the commits indexed and remote the commits the refs
+	// pointed to when last checked
+	commits map[string]string
+	remote  map[string]string
+	sync.Mutex
+}
+
+// NewGit returns a Source cloning the ref of each slug, from the URL with
+// {slug} replaced by the slug
+func NewGit(url, ref string, slugs []string) *Git {
+	if ref == "" {
+		ref = defaultGitRef
+	}
+	return &Git{
+		url:     url,
+		ref:     ref,
+		slugs:   slugs,
+		rev:     1,
+		commits: make(map[string]string),
+		remote:  make(map[string]string),
+	}
+}
+
+// List returns the configured slugs
+func (s *Git) List() ([]string, error) {
+	return append([]string(nil), s.slugs...), nil
+}
+
+// Revision returns the number of checks which found a ref had moved
+func (s *Git) Revision() (int, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.rev, nil
+}
+
+// Changes returns the Extensions whose ref has moved since it was last
+// indexed, or which have not been indexed since starting. The revision goes
+// up when a ref has moved since the last check, so the changes found may be
+// newer than to. Extensions which cannot be reached are left to be checked
+// next time.
+func (s *Git) Changes(from, to int) (map[string]int, error) {
+	// Carry on from the revision the Repo reached before a restart
+	s.Lock()
+	if from > s.rev {
+		s.rev = from
+	}
+	s.Unlock()
+
+	var changed []string
+	var moved bool
+	for _, slug := range s.slugs {
+		commit, err := s.remoteCommit(slug)
+		if err != nil {
+			continue
+		}
+
+		s.Lock()
+		if commit != s.commits[slug] {
+			changed = append(changed, slug)
+			if commit != s.remote[slug] {
+				moved = true
+			}
+		}
+		s.remote[slug] = commit
+		s.Unlock()
+	}
+
+	s.Lock()
+	if moved {
+		s.rev++
+	}
+	rev := s.rev
+	s.Unlock()
+
+	list := make(map[string]int, len(changed))
+	for _, slug := range changed {
+		list[slug] = rev
+	}
+
+	return list, nil
+}
+
+// Info returns metadata holding the slug and the version, which is the
+// ref name
+func (s *Git) Info(slug string) ([]byte, error) {
+	if err := checkSlug(slug); err != nil {
+		return nil, err
+	}
+	return minimalInfo(slug, s.ref)
+}
+
+// Archive clones the ref of an Extension
+func (s *Git) Archive(slug string) ([]byte, error) {
+	b, _, err := s.Checkout(slug)
+	return b, err
+}
+
+// Checkout clones the ref of an Extension, returning it with the commit
+// checked out
+func (s *Git) Checkout(slug string) ([]byte, *Ref, error) {
+	if err := checkSlug(slug); err != nil {
+		return nil, nil, err
+	}
+
+	tmp, err := ioutil.TempDir("", "wpdir-git")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	dst := filepath.Join(tmp, slug)
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if s.ref != defaultGitRef {
+		args = append(args, "--branch", s.ref)
+	}
+	args = append(args, s.repoURL(slug), dst)
+	if _, err := run("git", args...); err != nil {
+		return nil, nil, err
+	}
+
+	out, err := run("git", "-C", dst, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, nil, err
+	}
+	ref := &Ref{
+		Name:   s.ref,
+		Commit: strings.TrimSpace(string(out)),
+	}
+
+	b, err := zipDir(dst, slug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return b, ref, nil
+}
+
+// Seen records the commit of an Extension which has been indexed, so it is
+// not found by Changes until the ref moves again
+func (s *Git) Seen(slug, commit string) {
+	s.Lock()
+	defer s.Unlock()
+	s.commits[slug] = commit
+}
+
+// remoteCommit returns the commit the ref of an Extension points to,
+// annotated tags are resolved to their commit
+func (s *Git) remoteCommit(slug string) (string, error) {
+	out, err := run("git", "ls-remote", s.repoURL(slug), s.ref, s.ref+"^{}")
+	if err != nil {
+		return "", err
+	}
+
+	var commit string
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if strings.HasSuffix(fields[1], "^{}") {
+			return fields[0], nil
+		}
+		if commit == "" {
+			commit = fields[0]
+		}
+	}
+
+	return commit, nil
+}
+
+func (s *Git) repoURL(slug string) string {
+	return strings.Replace(s.url, slugPlaceholder, slug, -1)
+}
@@ -0,0 +1,149 @@
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	archiveExt = ".zip"
+	infoExt    = ".json"
+)
+
+// Local reads Extensions from a directory holding an archive named
+// <slug>.zip for each Extension, alongside an optional <slug>.json sidecar
+// with its metadata in the format of the WordPress.org info API.
+// Revisions are the Unix times files were last modified, so replacing an
+// archive or sidecar queues the Extension for updating.
+type Local struct {
+	dir string
+}
+
+// NewLocal returns a Source reading from a directory
+func NewLocal(dir string) *Local {
+	return &Local{dir: dir}
+}
+
+// List returns the slug of every archive
+func (s *Local) List() ([]string, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var slugs []string
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != archiveExt {
+			continue
+		}
+		slugs = append(slugs, strings.TrimSuffix(f.Name(), archiveExt))
+	}
+
+	return slugs, nil
+}
+
+// Revision returns the time the most recently modified file was changed
+func (s *Local) Revision() (int, error) {
+	revs, err := s.revisions()
+	if err != nil {
+		return 0, err
+	}
+
+	var latest int
+	for _, rev := range revs {
+		if rev > latest {
+			latest = rev
+		}
+	}
+
+	return latest, nil
+}
+
+// Changes returns the Extensions whose archive or sidecar was modified
+// between two revisions
+func (s *Local) Changes(from, to int) (map[string]int, error) {
+	revs, err := s.revisions()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make(map[string]int)
+	for slug, rev := range revs {
+		if rev > from && rev <= to {
+			list[slug] = rev
+		}
+	}
+
+	return list, nil
+}
+
+// Info reads the sidecar of an Extension, if there is none the metadata
+// only holds the slug
+func (s *Local) Info(slug string) ([]byte, error) {
+	path, err := s.path(slug, infoExt)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return minimalInfo(slug, "")
+	}
+
+	return b, err
+}
+
+// Archive reads the archive of an Extension
+func (s *Local) Archive(slug string) ([]byte, error) {
+	path, err := s.path(slug, archiveExt)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return b, err
+}
+
+// revisions returns the revision of each Extension, which is the latest
+// modification time of its archive and sidecar
+func (s *Local) revisions() (map[string]int, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	revs := make(map[string]int)
+	for _, f := range files {
+		ext := filepath.Ext(f.Name())
+		if f.IsDir() || (ext != archiveExt && ext != infoExt) {
+			continue
+		}
+		slug := strings.TrimSuffix(f.Name(), ext)
+		if rev := int(f.ModTime().Unix()); rev > revs[slug] {
+			revs[slug] = rev
+		}
+	}
+
+	// Sidecars without an archive are not Extensions
+	for slug := range revs {
+		if _, err := os.Stat(filepath.Join(s.dir, slug+archiveExt)); err != nil {
+			delete(revs, slug)
+		}
+	}
+
+	return revs, nil
+}
+
+// path returns the path of an Extension file
+func (s *Local) path(slug, ext string) (string, error) {
+	if err := checkSlug(slug); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, slug+ext), nil
+}
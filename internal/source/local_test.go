@@ -0,0 +1,126 @@
+package source
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+// testLocal returns a Local Source over a directory holding the given
+// files, each modified at its given Unix time
+func testLocal(t *testing.T, files map[string]int64) (*Local, func()) {
+	dir, err := ioutil.TempDir("", "wpdir-source")
+	if err != nil {
+		t.Fatalf("Could not create dir: %s", err)
+	}
+	for name, mod := range files {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("Could not write %s: %s", name, err)
+		}
+		mt := time.Unix(mod, 0)
+		if err := os.Chtimes(path, mt, mt); err != nil {
+			t.Fatalf("Could not set time of %s: %s", name, err)
+		}
+	}
+	return NewLocal(dir), func() { os.RemoveAll(dir) }
+}
+
+func TestLocalList(t *testing.T) {
+	s, cleanup := testLocal(t, map[string]int64{
+		"akismet.zip":      100,
+		"akismet.json":     300,
+		"hello-dolly.zip":  200,
+		"orphan.json":      400,
+		"readme.txt":       500,
+		"private-tool.zip": 150,
+	})
+	defer cleanup()
+
+	slugs, err := s.List()
+	if err != nil {
+		t.Fatalf("Could not list: %s", err)
+	}
+	sort.Strings(slugs)
+	expected := []string{"akismet", "hello-dolly", "private-tool"}
+	if len(slugs) != len(expected) {
+		t.Fatalf("Expected %+v got %+v", expected, slugs)
+	}
+	for i := range slugs {
+		if slugs[i] != expected[i] {
+			t.Errorf("Expected %+v got %+v", expected, slugs)
+		}
+	}
+
+	rev, err := s.Revision()
+	if err != nil {
+		t.Fatalf("Could not get revision: %s", err)
+	}
+	if rev != 300 {
+		t.Errorf("Expected %+v got %+v", 300, rev)
+	}
+
+	tests := []struct {
+		from, to int
+		expected map[string]int
+	}{
+		{0, 300, map[string]int{"akismet": 300, "hello-dolly": 200, "private-tool": 150}},
+		{150, 300, map[string]int{"akismet": 300, "hello-dolly": 200}},
+		{200, 250, map[string]int{}},
+		{300, 300, map[string]int{}},
+	}
+
+	for _, test := range tests {
+		changes, err := s.Changes(test.from, test.to)
+		if err != nil {
+			t.Fatalf("Could not get changes: %s", err)
+		}
+		if len(changes) != len(test.expected) {
+			t.Errorf("Expected %+v got %+v", test.expected, changes)
+			continue
+		}
+		for slug, rev := range test.expected {
+			if changes[slug] != rev {
+				t.Errorf("Expected %+v got %+v", test.expected, changes)
+			}
+		}
+	}
+}
+
+func TestLocalFiles(t *testing.T) {
+	s, cleanup := testLocal(t, map[string]int64{
+		"akismet.zip":     100,
+		"akismet.json":    100,
+		"hello-dolly.zip": 100,
+	})
+	defer cleanup()
+
+	if b, err := s.Archive("akismet"); err != nil || string(b) != "akismet.zip" {
+		t.Errorf("Expected %+v got %+v (%v)", "akismet.zip", string(b), err)
+	}
+	if b, err := s.Archive("missing"); err != nil || len(b) != 0 {
+		t.Errorf("Expected an empty archive got %+v (%v)", string(b), err)
+	}
+	if b, err := s.Info("akismet"); err != nil || string(b) != "akismet.json" {
+		t.Errorf("Expected %+v got %+v (%v)", "akismet.json", string(b), err)
+	}
+
+	b, err := s.Info("hello-dolly")
+	if err != nil {
+		t.Fatalf("Could not get info: %s", err)
+	}
+	var info map[string]string
+	if err := json.Unmarshal(b, &info); err != nil || info["slug"] != "hello-dolly" {
+		t.Errorf("Expected info with slug %+v got %+v (%v)", "hello-dolly", string(b), err)
+	}
+
+	for _, slug := range []string{"", "../akismet", "a/b", ".hidden"} {
+		if _, err := s.Archive(slug); err == nil {
+			t.Errorf("Expected an error for slug %q", slug)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package source
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/wpdirectory/wpdir/internal/config"
+)
+
+const (
+	// TypeWPOrg fetches Extensions from the WordPress.org API and downloads
+	TypeWPOrg = "wporg"
+	// TypeLocal reads Extensions from a directory of archives
+	TypeLocal = "local"
+	// TypeSVN exports Extensions from an SVN repository
+	TypeSVN = "svn"
+	// TypeGit clones Extensions from Git repositories
+	TypeGit = "git"
+)
+
+// Source provides the Extensions of a Repo
+type Source interface {
+	// List returns the slugs of every Extension
+	List() ([]string, error)
+	// Revision returns the latest revision of the Source
+	Revision() (int, error)
+	// Changes returns the slugs changed after the from revision, up to and
+	// including the to revision, with the revision of their latest change
+	Changes(from, to int) (map[string]int, error)
+	// Info returns the metadata of an Extension, in the format of the
+	// WordPress.org info API
+	Info(slug string) ([]byte, error)
+	// Archive returns the latest archive of an Extension
+	// An empty archive is returned if it is no longer available.
+	Archive(slug string) ([]byte, error)
+}
+
+// Checkout is implemented by Sources fetching Extensions from version
+// control, it returns the archive along with the Ref it was built from
+type Checkout interface {
+	Checkout(slug string) ([]byte, *Ref, error)
+}
+
+// Tracker is implemented by Sources which find changes by comparing against
+// the commits indexed. Seen records the commit of an Extension once its new
+// index is in use.
+type Tracker interface {
+	Seen(slug, commit string)
+}
+
+// Ref identifies the version control ref checked out
+type Ref struct {
+	// Name is the SVN path or Git branch or tag, such as trunk or tags/1.0
+	Name string
+	// Commit is the SVN revision or Git commit hash
+	Commit string
+}
+
+// New returns the Source set in the config for a Repo of the given
+// Extension type, WordPress.org is used if none is set
+func New(c config.Source, extType, agent string) (Source, error) {
+	switch c.Type {
+	case "", TypeWPOrg:
+		return NewWPOrg(extType, agent), nil
+	case TypeLocal:
+		if c.Dir == "" {
+			return nil, fmt.Errorf("source for %s has no dir", extType)
+		}
+		return NewLocal(c.Dir), nil
+	case TypeSVN:
+		if c.URL == "" {
+			return nil, fmt.Errorf("source for %s has no url", extType)
+		}
+		return NewSVN(c.URL, c.Ref, c.Slugs), nil
+	case TypeGit:
+		if !strings.Contains(c.URL, slugPlaceholder) {
+			return nil, fmt.Errorf("source for %s needs a url containing %s", extType, slugPlaceholder)
+		}
+		if len(c.Slugs) == 0 {
+			return nil, fmt.Errorf("source for %s has no slugs", extType)
+		}
+		return NewGit(c.URL, c.Ref, c.Slugs), nil
+	default:
+		return nil, fmt.Errorf("source type not recognized: %s", c.Type)
+	}
+}
+
+// minimalInfo returns metadata for an Extension which has none of its own
+func minimalInfo(slug, version string) ([]byte, error) {
+	info := map[string]string{
+		"slug": slug,
+		"name": slug,
+	}
+	if version != "" {
+		info["version"] = version
+	}
+	return json.Marshal(info)
+}
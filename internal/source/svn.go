@@ -0,0 +1,187 @@
+package source
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultSVNRef is exported when no ref is set
+const defaultSVNRef = "trunk"
+
+var exportedRevision = regexp.MustCompile(`Exported revision (\d+)\.`)
+
+// SVN exports Extensions from an SVN repository with a directory per
+// Extension, laid out like the WordPress.org repositories with trunk, tags
+// and branches within each. Revisions are those of the SVN repository.
+type SVN struct {
+	url   string
+	ref   string
+	slugs []string
+}
+
+// NewSVN returns a Source exporting the ref of each Extension in an SVN
+// repository, limited to the slugs given if there are any
+func NewSVN(url, ref string, slugs []string) *SVN {
+	if ref == "" {
+		ref = defaultSVNRef
+	}
+	return &SVN{
+		url:   strings.TrimSuffix(url, "/"),
+		ref:   strings.Trim(ref, "/"),
+		slugs: slugs,
+	}
+}
+
+// List returns the configured slugs, or every directory in the repository
+func (s *SVN) List() ([]string, error) {
+	if len(s.slugs) > 0 {
+		return append([]string(nil), s.slugs...), nil
+	}
+
+	out, err := run("svn", "list", "--non-interactive", s.url)
+	if err != nil {
+		return nil, err
+	}
+
+	var slugs []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasSuffix(line, "/") {
+			slugs = append(slugs, strings.TrimSuffix(line, "/"))
+		}
+	}
+
+	return slugs, nil
+}
+
+// Revision returns the latest revision of the repository
+func (s *SVN) Revision() (int, error) {
+	out, err := run("svn", "info", "--non-interactive", "--show-item", "revision", s.url)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+// svnLog is the XML output of svn log
+type svnLog struct {
+	Entries []struct {
+		Revision int      `xml:"revision,attr"`
+		Paths    []string `xml:"paths>path"`
+	} `xml:"logentry"`
+}
+
+// Changes reads the log for changes to the ref of each Extension
+func (s *SVN) Changes(from, to int) (map[string]int, error) {
+	list := make(map[string]int)
+	if from >= to {
+		return list, nil
+	}
+
+	// Log paths are relative to the repository root, which may be above
+	// the Source URL
+	out, err := run("svn", "info", "--non-interactive", "--show-item", "repos-root-url", s.url)
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.TrimPrefix(s.url, strings.TrimSpace(string(out))) + "/"
+
+	out, err = run("svn", "log", "--non-interactive", "--xml", "--quiet", "--verbose",
+		"--revision", fmt.Sprintf("%d:%d", from+1, to), s.url)
+	if err != nil {
+		return nil, err
+	}
+
+	var log svnLog
+	if err := xml.Unmarshal(out, &log); err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(s.slugs))
+	for _, slug := range s.slugs {
+		wanted[slug] = true
+	}
+
+	for _, entry := range log.Entries {
+		for _, path := range entry.Paths {
+			if !strings.HasPrefix(path, prefix) {
+				continue
+			}
+			parts := strings.SplitN(strings.TrimPrefix(path, prefix), "/", 2)
+			if len(parts) < 2 || !(parts[1] == s.ref || strings.HasPrefix(parts[1], s.ref+"/")) {
+				continue
+			}
+			slug := parts[0]
+			if len(wanted) > 0 && !wanted[slug] {
+				continue
+			}
+			if entry.Revision > list[slug] {
+				list[slug] = entry.Revision
+			}
+		}
+	}
+
+	return list, nil
+}
+
+// Info returns metadata holding the slug and the version, which is the tag
+// or branch name or trunk
+func (s *SVN) Info(slug string) ([]byte, error) {
+	if err := checkSlug(slug); err != nil {
+		return nil, err
+	}
+	return minimalInfo(slug, svnVersion(s.ref))
+}
+
+// Archive exports the ref of an Extension
+func (s *SVN) Archive(slug string) ([]byte, error) {
+	b, _, err := s.Checkout(slug)
+	return b, err
+}
+
+// Checkout exports the ref of an Extension, returning it with the revision
+// exported
+func (s *SVN) Checkout(slug string) ([]byte, *Ref, error) {
+	if err := checkSlug(slug); err != nil {
+		return nil, nil, err
+	}
+
+	tmp, err := ioutil.TempDir("", "wpdir-svn")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	dst := filepath.Join(tmp, slug)
+	out, err := run("svn", "export", "--non-interactive", s.url+"/"+slug+"/"+s.ref, dst)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ref := &Ref{Name: s.ref}
+	if m := exportedRevision.FindSubmatch(out); m != nil {
+		ref.Commit = string(m[1])
+	}
+
+	b, err := zipDir(dst, slug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return b, ref, nil
+}
+
+// svnVersion returns the version name of a ref, tags/1.0 is version 1.0
+func svnVersion(ref string) string {
+	for _, dir := range []string{"tags/", "branches/"} {
+		if strings.HasPrefix(ref, dir) {
+			return strings.TrimPrefix(ref, dir)
+		}
+	}
+	return ref
+}
@@ -0,0 +1,111 @@
+package source
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// commandTimeout limits how long a single version control command may run
+const commandTimeout = 10 * time.Minute
+
+// vcsDirs are the version control metadata dirs left out of archives
+var vcsDirs = map[string]bool{
+	".git": true,
+	".svn": true,
+}
+
+// checkSlug rejects slugs which could point outside a directory or URL path
+func checkSlug(slug string) error {
+	if slug == "" || slug != filepath.Base(slug) || strings.HasPrefix(slug, ".") || strings.ContainsAny(slug, `/\`) {
+		return fmt.Errorf("invalid slug: %s", slug)
+	}
+	return nil
+}
+
+// run executes a version control command without prompting for input,
+// returning its output. Errors include what the command wrote to stderr.
+func run(name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s %s failed: %s: %s", name, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// zipDir archives a checkout with its files in a dir named after the slug,
+// matching the layout of WordPress.org archives
+func zipDir(dir, slug string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && vcsDirs[info.Name()] {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name := slug
+		if rel != "." {
+			name += "/" + filepath.ToSlash(rel)
+		}
+
+		// Skip symlinks and other special files
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if info.IsDir() {
+			hdr.Name += "/"
+			_, err = zw.CreateHeader(hdr)
+			return err
+		}
+		hdr.Method = zip.Deflate
+
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(w, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
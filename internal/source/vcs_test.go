@@ -0,0 +1,265 @@
+package source
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// command runs a command in dir, failing the test if it errors
+func command(t *testing.T, dir, name string, args ...string) string {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s %s failed: %s: %s", name, strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// writeFiles writes files relative to dir, creating their parent dirs
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Could not create dir: %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Could not write %s: %s", name, err)
+		}
+	}
+}
+
+// zipContents returns the names and contents of the files in an archive,
+// directories map to an empty string
+func zipContents(t *testing.T, b []byte) map[string]string {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("Could not read archive: %s", err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("Could not open %s: %s", f.Name, err)
+		}
+		content, _ := ioutil.ReadAll(r)
+		r.Close()
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func sameFiles(expected, got map[string]string) bool {
+	if len(expected) != len(got) {
+		return false
+	}
+	for name, content := range expected {
+		if c, ok := got[name]; !ok || c != content {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGitCheckout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "wpdir-git-test")
+	if err != nil {
+		t.Fatalf("Could not create dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Build a bare repository with a tagged release and a later commit
+	work := filepath.Join(dir, "work")
+	bare := filepath.Join(dir, "repos", "hello.git")
+	os.MkdirAll(work, 0755)
+	command(t, work, "git", "init", "--quiet")
+	writeFiles(t, work, map[string]string{
+		"hello.php":     "<?php // 1.0\n",
+		"inc/admin.php": "<?php\n",
+	})
+	command(t, work, "git", "add", "-A")
+	command(t, work, "git", "commit", "--quiet", "-m", "Release 1.0")
+	command(t, work, "git", "tag", "-a", "v1.0", "-m", "1.0")
+	writeFiles(t, work, map[string]string{"hello.php": "<?php // fix\n"})
+	command(t, work, "git", "commit", "--quiet", "-am", "Unreleased fix")
+	head := command(t, work, "git", "rev-parse", "HEAD")
+	tagged := command(t, work, "git", "rev-parse", "v1.0^{commit}")
+	command(t, dir, "git", "clone", "--quiet", "--bare", work, bare)
+
+	tests := []struct {
+		ref     string
+		name    string
+		commit  string
+		version string
+		files   map[string]string
+	}{
+		{"", defaultGitRef, head, defaultGitRef, map[string]string{
+			"hello/":              "",
+			"hello/hello.php":     "<?php // fix\n",
+			"hello/inc/":          "",
+			"hello/inc/admin.php": "<?php\n",
+		}},
+		{"v1.0", "v1.0", tagged, "v1.0", map[string]string{
+			"hello/":              "",
+			"hello/hello.php":     "<?php // 1.0\n",
+			"hello/inc/":          "",
+			"hello/inc/admin.php": "<?php\n",
+		}},
+	}
+
+	for _, test := range tests {
+		s := NewGit("file://"+filepath.Join(dir, "repos", "{slug}.git"), test.ref, []string{"hello"})
+
+		// The first check finds the ref, which moves the revision on
+		rev, err := s.Revision()
+		if err != nil || rev != 1 {
+			t.Errorf("Expected revision 1 got %d (%v)", rev, err)
+		}
+		changes, err := s.Changes(0, rev)
+		if err != nil || changes["hello"] != 2 {
+			t.Errorf("Expected hello to have changed before checkout got %+v (%v)", changes, err)
+		}
+
+		b, ref, err := s.Checkout("hello")
+		if err != nil {
+			t.Fatalf("Could not checkout %s: %s", test.ref, err)
+		}
+		if ref.Name != test.name || ref.Commit != test.commit {
+			t.Errorf("Expected %+v got %+v", &Ref{test.name, test.commit}, ref)
+		}
+		if files := zipContents(t, b); !sameFiles(test.files, files) {
+			t.Errorf("Expected %+v got %+v", test.files, files)
+		}
+
+		// Checking out alone, as archives do, does not record the commit
+		// The ref has not moved again, so neither has the revision.
+		if changes, err := s.Changes(2, 2); err != nil || changes["hello"] != 2 {
+			t.Errorf("Expected hello to have changed before being seen got %+v (%v)", changes, err)
+		}
+		s.Seen("hello", ref.Commit)
+		if changes, err := s.Changes(2, 2); err != nil || len(changes) != 0 {
+			t.Errorf("Expected no changes once seen got %+v (%v)", changes, err)
+		}
+		if rev, err := s.Revision(); err != nil || rev != 2 {
+			t.Errorf("Expected revision 2 got %d (%v)", rev, err)
+		}
+
+		info, err := s.Info("hello")
+		if err != nil || !strings.Contains(string(info), `"version":"`+test.version+`"`) {
+			t.Errorf("Expected version %s got %s (%v)", test.version, info, err)
+		}
+	}
+
+	// After a restart the indexed commit is seen and the revision carries
+	// on from the one reached, until a push changes the remote HEAD
+	s := NewGit("file://"+filepath.Join(dir, "repos", "{slug}.git"), "", []string{"hello"})
+	_, ref, err := s.Checkout("hello")
+	if err != nil {
+		t.Fatalf("Could not checkout: %s", err)
+	}
+	s.Seen("hello", ref.Commit)
+	if changes, err := s.Changes(100, 100); err != nil || len(changes) != 0 {
+		t.Errorf("Expected no changes after a restart got %+v (%v)", changes, err)
+	}
+	writeFiles(t, work, map[string]string{"readme.txt": "Hello\n"})
+	command(t, work, "git", "add", "-A")
+	command(t, work, "git", "commit", "--quiet", "-m", "Readme")
+	command(t, work, "git", "push", "--quiet", bare, "HEAD")
+	if changes, err := s.Changes(100, 100); err != nil || changes["hello"] != 101 {
+		t.Errorf("Expected hello to have changed after a push got %+v (%v)", changes, err)
+	}
+	if rev, err := s.Revision(); err != nil || rev != 101 {
+		t.Errorf("Expected revision 101 got %d (%v)", rev, err)
+	}
+
+	if _, _, err := s.Checkout("../hello"); err == nil {
+		t.Errorf("Expected an error for an invalid slug")
+	}
+}
+
+func TestSVNCheckout(t *testing.T) {
+	for _, name := range []string{"svn", "svnadmin"} {
+		if _, err := exec.LookPath(name); err != nil {
+			t.Skipf("%s is not installed", name)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "wpdir-svn-test")
+	if err != nil {
+		t.Fatalf("Could not create dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Build a repository laid out like WordPress.org, committing trunk
+	// after the tag
+	repo := filepath.Join(dir, "repo")
+	url := "file://" + repo
+	command(t, dir, "svnadmin", "create", repo)
+	tree := filepath.Join(dir, "tree")
+	writeFiles(t, tree, map[string]string{
+		"hello/tags/1.0/hello.php": "<?php // 1.0\n",
+		"hello/trunk/hello.php":    "<?php // 1.0\n",
+		"other/trunk/other.php":    "<?php\n",
+	})
+	command(t, dir, "svn", "import", "--quiet", "-m", "Import", tree, url)
+	command(t, dir, "svn", "checkout", "--quiet", url+"/hello/trunk", "wc")
+	writeFiles(t, filepath.Join(dir, "wc"), map[string]string{"hello.php": "<?php // fix\n"})
+	command(t, filepath.Join(dir, "wc"), "svn", "commit", "--quiet", "-m", "Unreleased fix")
+
+	s := NewSVN(url, "", nil)
+	slugs, err := s.List()
+	sort.Strings(slugs)
+	if err != nil || len(slugs) != 2 || slugs[0] != "hello" || slugs[1] != "other" {
+		t.Errorf("Expected %+v got %+v (%v)", []string{"hello", "other"}, slugs, err)
+	}
+	if rev, err := s.Revision(); err != nil || rev != 2 {
+		t.Errorf("Expected revision %d got %d (%v)", 2, rev, err)
+	}
+	if changes, err := s.Changes(1, 2); err != nil || len(changes) != 1 || changes["hello"] != 2 {
+		t.Errorf("Expected %+v got %+v (%v)", map[string]int{"hello": 2}, changes, err)
+	}
+
+	tests := []struct {
+		ref     string
+		name    string
+		version string
+		content string
+	}{
+		{"", "trunk", "trunk", "<?php // fix\n"},
+		{"tags/1.0", "tags/1.0", "1.0", "<?php // 1.0\n"},
+	}
+
+	for _, test := range tests {
+		s := NewSVN(url, test.ref, []string{"hello"})
+		b, ref, err := s.Checkout("hello")
+		if err != nil {
+			t.Fatalf("Could not checkout %s: %s", test.ref, err)
+		}
+		if ref.Name != test.name || ref.Commit != "2" {
+			t.Errorf("Expected %+v got %+v", &Ref{test.name, "2"}, ref)
+		}
+		expected := map[string]string{"hello/": "", "hello/hello.php": test.content}
+		if files := zipContents(t, b); !sameFiles(expected, files) {
+			t.Errorf("Expected %+v got %+v", expected, files)
+		}
+		info, err := s.Info("hello")
+		if err != nil || !strings.Contains(string(info), `"version":"`+test.version+`"`) {
+			t.Errorf("Expected version %s got %s (%v)", test.version, info, err)
+		}
+	}
+}
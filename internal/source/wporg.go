@@ -0,0 +1,116 @@
+package source
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/wpdirectory/wpdir/internal/client"
+	"github.com/wpdirectory/wpdir/internal/utils"
+	"github.com/wpdirectory/wporg"
+)
+
+var (
+	archiveURL = "http://downloads.wordpress.org/%s/%s.latest-stable.zip?nostats=1"
+)
+
+// WPOrg fetches Extensions from WordPress.org
+type WPOrg struct {
+	extType string
+	agent   string
+	api     *wporg.Client
+}
+
+// NewWPOrg returns a WordPress.org Source for plugins or themes
+func NewWPOrg(extType, agent string) *WPOrg {
+	// Setup HTTP Client
+	opt := func(c *wporg.Client) {
+		c.HTTPClient = client.GetAPI()
+	}
+
+	return &WPOrg{
+		extType: extType,
+		agent:   agent,
+		api:     wporg.NewClient(opt),
+	}
+}
+
+// List returns every slug listed by the API
+func (s *WPOrg) List() ([]string, error) {
+	return s.api.GetList(s.extType)
+}
+
+// Revision returns the latest SVN revision
+func (s *WPOrg) Revision() (int, error) {
+	return s.api.GetRevision(s.extType)
+}
+
+// Changes reads the SVN changelog between two revisions
+func (s *WPOrg) Changes(from, to int) (map[string]int, error) {
+	log, err := s.api.GetChangeLog(s.extType, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove Duplicates
+	// Save most recent revision
+	list := make(map[string]int)
+	for _, update := range log {
+		rev, err := strconv.Atoi(update[1])
+		if err != nil {
+			return nil, fmt.Errorf("revision not a valid int: %s", err)
+		}
+		list[update[0]] = rev
+	}
+
+	return list, nil
+}
+
+// Info fetches the API info of an Extension
+func (s *WPOrg) Info(slug string) ([]byte, error) {
+	return s.api.GetInfo(s.extType, slug)
+}
+
+// Archive downloads the latest stable archive of an Extension
+func (s *WPOrg) Archive(slug string) ([]byte, error) {
+	var content []byte
+	var err error
+
+	client := client.GetZip()
+	repo := s.extType[:len(s.extType)-1]
+	URL := fmt.Sprintf(archiveURL, repo, slug)
+
+	req, err := http.NewRequest("GET", URL, nil)
+	if err != nil {
+		return content, err
+	}
+
+	// Set User-Agent
+	req.Header.Set("User-Agent", s.agent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return content, err
+	}
+	defer utils.CheckClose(resp.Body, &err)
+
+	if resp.StatusCode != 200 {
+		// Code 404 is acceptable, it means the plugin/theme is no longer available.
+		if resp.StatusCode == 404 {
+			return content, nil
+		}
+
+		log.Printf("Downloading the extension '%s' failed. Response code: %d\n", slug, resp.StatusCode)
+
+		return content, err
+	}
+
+	content, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return content, err
+	}
+
+	return content, nil
+}
@@ -0,0 +1,327 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/wpdirectory/wpdir/internal/config"
+	"github.com/wpdirectory/wpdir/internal/db"
+	"github.com/wpdirectory/wpdir/internal/ulid"
+)
+
+const (
+	// SearchCompleted is sent when a Search finishes, was cancelled or
+	// timed out
+	SearchCompleted = "search.completed"
+	// WatchMatch is sent when a Watch finds new Matches
+	WatchMatch = "watch.match"
+	// ExtensionUpdated is sent when an Extension has been updated
+	ExtensionUpdated = "extension.updated"
+	// ExtensionClosed is sent when an Extension could not be updated
+	ExtensionClosed = "extension.closed"
+)
+
+// Events lists every event type which may be sent
+var Events = []string{SearchCompleted, WatchMatch, ExtensionUpdated, ExtensionClosed}
+
+const (
+	// SignatureHeader holds the hex HMAC-SHA256 of the body, using the
+	// webhook secret, prefixed with sha256=
+	SignatureHeader = "X-Wpdir-Signature"
+	// EventHeader holds the event type
+	EventHeader = "X-Wpdir-Event"
+	// DeliveryHeader holds the delivery ID, which is the same for retries
+	DeliveryHeader = "X-Wpdir-Delivery"
+
+	queueSize   = 10000
+	maxAttempts = 5
+	baseBackoff = 2 * time.Second
+)
+
+// Payload is the JSON body POSTed to webhooks
+type Payload struct {
+	ID      string      `json:"id"`
+	Event   string      `json:"event"`
+	Created string      `json:"created"`
+	Data    interface{} `json:"data"`
+}
+
+// Delivery records the attempts made to deliver a Payload to a webhook
+type Delivery struct {
+	ID         string `json:"id"`
+	URL        string `json:"url"`
+	Event      string `json:"event"`
+	PayloadID  string `json:"payload_id"`
+	Attempts   int    `json:"attempts"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Err        string `json:"error,omitempty"`
+	Delivered  bool   `json:"delivered"`
+	Created    string `json:"created"`
+	Updated    string `json:"updated"`
+}
+
+// Dispatcher queues Payloads and delivers them to the webhooks subscribed
+// to their event. Each webhook has its own queue, so a slow or failing
+// webhook does not hold up the others. Deliveries are not ordered.
+// Queued Payloads and pending retries are only held in memory, they do not
+// survive a restart and their last attempt stays undelivered in the log.
+type Dispatcher struct {
+	hooks       []config.Webhook
+	client      *http.Client
+	queues      []chan *job
+	maxAttempts int
+	backoff     time.Duration
+	save        func(d *Delivery) error
+}
+
+// job is a Payload waiting to be delivered to a webhook
+type job struct {
+	hook     config.Webhook
+	queue    chan *job
+	delivery *Delivery
+	body     []byte
+	backoff  time.Duration
+}
+
+var dispatcher *Dispatcher
+
+// Setup starts delivering events to the webhooks
+func Setup(hooks []config.Webhook) {
+	if len(hooks) == 0 {
+		return
+	}
+	dispatcher = New(hooks)
+	dispatcher.Start()
+}
+
+// Send queues an event for every webhook subscribed to it
+// It does nothing if no webhooks are set up.
+func Send(event string, data interface{}) {
+	if dispatcher != nil {
+		dispatcher.Send(event, data)
+	}
+}
+
+// New returns a Dispatcher for the webhooks
+func New(hooks []config.Webhook) *Dispatcher {
+	for _, h := range hooks {
+		for _, event := range h.Events {
+			if !validEvent(event) {
+				log.Printf("Webhook %s has an unknown event: %s\n", h.URL, event)
+			}
+		}
+	}
+
+	queues := make([]chan *job, len(hooks))
+	for i := range queues {
+		queues[i] = make(chan *job, queueSize)
+	}
+
+	return &Dispatcher{
+		hooks:       hooks,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		queues:      queues,
+		maxAttempts: maxAttempts,
+		backoff:     baseBackoff,
+		save:        saveDelivery,
+	}
+}
+
+// Start starts a Goroutine delivering the queued Payloads of each webhook
+func (d *Dispatcher) Start() {
+	for _, q := range d.queues {
+		go d.worker(q)
+	}
+}
+
+// Send queues a Payload for every webhook subscribed to the event
+// Payloads are dropped if the webhook queue is full, so callers never wait.
+func (d *Dispatcher) Send(event string, data interface{}) {
+	p := &Payload{
+		ID:      ulid.New(),
+		Event:   event,
+		Created: time.Now().Format(time.RFC3339),
+		Data:    data,
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		log.Printf("Failed Marshalling webhook payload: %s\n", err)
+		return
+	}
+
+	for i, h := range d.hooks {
+		if !subscribed(h, event) {
+			continue
+		}
+		j := &job{
+			hook:    h,
+			queue:   d.queues[i],
+			body:    body,
+			backoff: d.backoff,
+			delivery: &Delivery{
+				ID:        ulid.New(),
+				URL:       h.URL,
+				Event:     event,
+				PayloadID: p.ID,
+				Created:   p.Created,
+			},
+		}
+		d.enqueue(j)
+	}
+}
+
+// enqueue adds a job to its webhook queue without waiting
+// Jobs are dropped if the queue is full, the Delivery is saved so the
+// drop can be seen.
+func (d *Dispatcher) enqueue(j *job) {
+	select {
+	case j.queue <- j:
+		return
+	default:
+	}
+
+	dl := j.delivery
+	log.Printf("Webhook queue full, dropped %s for %s\n", dl.Event, dl.URL)
+	dl.Err = "webhook queue full"
+	dl.Updated = time.Now().Format(time.RFC3339)
+	if err := d.save(dl); err != nil {
+		log.Printf("Failed saving webhook delivery %s: %s\n", dl.ID, err)
+	}
+}
+
+// worker delivers the queued Payloads of a webhook
+// Failed attempts are queued again after a backoff, so the worker is free
+// to deliver other Payloads meanwhile.
+func (d *Dispatcher) worker(queue chan *job) {
+	for j := range queue {
+		if !d.deliver(j) {
+			continue
+		}
+		wait := j.backoff
+		j.backoff *= 2
+		time.AfterFunc(wait, func() {
+			d.enqueue(j)
+		})
+	}
+}
+
+// deliver makes an attempt to POST a Payload, reporting whether it should
+// be retried. Attempts are retried with exponential backoff until one is
+// accepted or maxAttempts is reached. Client errors other than 429 are not
+// retried. The Delivery is saved after every attempt.
+func (d *Dispatcher) deliver(j *job) bool {
+	code, err := d.post(j)
+
+	dl := j.delivery
+	dl.Attempts++
+	dl.StatusCode = code
+	dl.Err = ""
+	if err != nil {
+		dl.Err = err.Error()
+	}
+	dl.Delivered = err == nil
+	dl.Updated = time.Now().Format(time.RFC3339)
+	if err := d.save(dl); err != nil {
+		log.Printf("Failed saving webhook delivery %s: %s\n", dl.ID, err)
+	}
+
+	return !dl.Delivered && retryable(code) && dl.Attempts < d.maxAttempts
+}
+
+// post sends a single attempt, returning the response status code
+func (d *Dispatcher) post(j *job) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, j.hook.URL, bytes.NewReader(j.body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "wpdir-webhook")
+	req.Header.Set(EventHeader, j.delivery.Event)
+	req.Header.Set(DeliveryHeader, j.delivery.ID)
+	if j.hook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(j.hook.Secret, j.body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	// Drain the body so the connection can be reused
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value for a body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Deliveries returns up to limit of the most recent webhook deliveries
+func Deliveries(limit int) ([]*Delivery, error) {
+	list, err := db.GetDeliveries(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*Delivery, 0, len(list))
+	for _, b := range list {
+		var dl Delivery
+		if err = json.Unmarshal(b, &dl); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &dl)
+	}
+	return deliveries, nil
+}
+
+func saveDelivery(dl *Delivery) error {
+	b, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+	return db.SaveDelivery(dl.ID, b)
+}
+
+// retryable reports whether a failed attempt should be tried again,
+// a code of zero means no response was received
+func retryable(code int) bool {
+	return code == 0 || code == http.StatusTooManyRequests || code >= 500
+}
+
+// subscribed reports whether the webhook receives the event
+func subscribed(h config.Webhook, event string) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+	for _, e := range h.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func validEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
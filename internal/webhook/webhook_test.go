@@ -0,0 +1,218 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wpdirectory/wpdir/internal/config"
+)
+
+// receiver records the requests made to a test webhook, responding with
+// the given status codes in turn
+type receiver struct {
+	codes    []int
+	requests []*http.Request
+	bodies   [][]byte
+	sync.Mutex
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	rc.Lock()
+	defer rc.Unlock()
+	code := http.StatusOK
+	if n := len(rc.requests); n < len(rc.codes) {
+		code = rc.codes[n]
+	}
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+	w.WriteHeader(code)
+}
+
+// saved records the Deliveries saved by a test Dispatcher
+type saved struct {
+	list []Delivery
+	sync.Mutex
+}
+
+func (sv *saved) all() []Delivery {
+	sv.Lock()
+	defer sv.Unlock()
+	return append([]Delivery(nil), sv.list...)
+}
+
+// wait returns the saved Deliveries once the last attempt has been made,
+// or after a second
+func (sv *saved) wait(attempts int) []Delivery {
+	deadline := time.Now().Add(time.Second)
+	for {
+		list := sv.all()
+		if n := len(list); (n > 0 && list[n-1].Attempts >= attempts) || time.Now().After(deadline) {
+			return list
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func testDispatcher(hooks []config.Webhook) (*Dispatcher, *saved) {
+	sv := &saved{}
+
+	d := New(hooks)
+	d.backoff = time.Millisecond
+	d.save = func(dl *Delivery) error {
+		sv.Lock()
+		defer sv.Unlock()
+		sv.list = append(sv.list, *dl)
+		return nil
+	}
+	return d, sv
+}
+
+func TestDeliverSigned(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d, sv := testDispatcher([]config.Webhook{{URL: srv.URL, Secret: "secret1"}})
+	d.Send(SearchCompleted, map[string]string{"id": "01CMT"})
+	d.deliver(<-d.queues[0])
+
+	if len(rc.requests) != 1 {
+		t.Fatalf("Expected 1 request got %d", len(rc.requests))
+	}
+	r := rc.requests[0]
+	if r.Header.Get(EventHeader) != SearchCompleted {
+		t.Errorf("Expected %+v got %+v", SearchCompleted, r.Header.Get(EventHeader))
+	}
+	if want := Sign("secret1", rc.bodies[0]); r.Header.Get(SignatureHeader) != want {
+		t.Errorf("Expected %+v got %+v", want, r.Header.Get(SignatureHeader))
+	}
+
+	var p Payload
+	if err := json.Unmarshal(rc.bodies[0], &p); err != nil {
+		t.Fatalf("Could not decode payload: %s", err)
+	}
+	if p.Event != SearchCompleted || p.Data.(map[string]interface{})["id"] != "01CMT" {
+		t.Errorf("Unexpected payload %+v", p)
+	}
+
+	if list := sv.all(); len(list) != 1 || !list[0].Delivered || list[0].StatusCode != http.StatusOK {
+		t.Errorf("Unexpected deliveries %+v", list)
+	}
+}
+
+func TestDeliverRetries(t *testing.T) {
+	tests := []struct {
+		codes     []int
+		attempts  int
+		delivered bool
+	}{
+		{[]int{500, 503, 200}, 3, true},
+		{[]int{429, 200}, 2, true},
+		{[]int{400}, 1, false},
+		{[]int{500, 500, 500, 500, 500, 500}, maxAttempts, false},
+	}
+
+	for _, test := range tests {
+		rc := &receiver{codes: test.codes}
+		srv := httptest.NewServer(rc)
+
+		d, sv := testDispatcher([]config.Webhook{{URL: srv.URL}})
+		d.Start()
+		d.Send(ExtensionClosed, nil)
+		list := sv.wait(test.attempts)
+		srv.Close()
+
+		last := list[len(list)-1]
+		rc.Lock()
+		if len(rc.requests) != test.attempts || last.Attempts != test.attempts || last.Delivered != test.delivered {
+			t.Errorf("Expected %d attempts delivered %t got %d attempts delivered %t for %v", test.attempts, test.delivered, last.Attempts, last.Delivered, test.codes)
+		}
+		if r := rc.requests[0]; r.Header.Get(SignatureHeader) != "" {
+			t.Errorf("Expected no signature without a secret got %s", r.Header.Get(SignatureHeader))
+		}
+		rc.Unlock()
+		for _, dl := range list {
+			if dl.ID != last.ID {
+				t.Errorf("Expected retries to share delivery ID %s got %s", last.ID, dl.ID)
+			}
+		}
+	}
+}
+
+func TestSendSubscribed(t *testing.T) {
+	d, _ := testDispatcher([]config.Webhook{
+		{URL: "http://a.test", Events: []string{WatchMatch}},
+		{URL: "http://b.test"},
+		{URL: "http://c.test", Events: []string{SearchCompleted, ExtensionUpdated}},
+	})
+
+	tests := []struct {
+		event string
+		urls  []string
+	}{
+		{WatchMatch, []string{"http://a.test", "http://b.test"}},
+		{ExtensionUpdated, []string{"http://b.test", "http://c.test"}},
+		{ExtensionClosed, []string{"http://b.test"}},
+	}
+
+	for _, test := range tests {
+		d.Send(test.event, nil)
+		var urls []string
+		for _, q := range d.queues {
+			for len(q) > 0 {
+				urls = append(urls, (<-q).hook.URL)
+			}
+		}
+		if len(urls) != len(test.urls) {
+			t.Errorf("Expected %+v got %+v for %s", test.urls, urls, test.event)
+			continue
+		}
+		for i := range urls {
+			if urls[i] != test.urls[i] {
+				t.Errorf("Expected %+v got %+v for %s", test.urls, urls, test.event)
+			}
+		}
+	}
+}
+
+func TestDeliverIndependent(t *testing.T) {
+	// The first webhook does not respond until the test is done
+	done := make(chan struct{})
+	stuck := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer stuck.Close()
+	defer close(done)
+
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d, sv := testDispatcher([]config.Webhook{{URL: stuck.URL}, {URL: srv.URL}})
+	d.Start()
+	for i := 0; i < 3; i++ {
+		d.Send(ExtensionUpdated, nil)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	var delivered int
+	for delivered < 3 && time.Now().Before(deadline) {
+		delivered = 0
+		for _, dl := range sv.all() {
+			if dl.URL == srv.URL && dl.Delivered {
+				delivered++
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if delivered != 3 {
+		t.Errorf("Expected %d deliveries got %d", 3, delivered)
+	}
+}
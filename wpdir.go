@@ -16,6 +16,7 @@ import (
 	"github.com/wpdirectory/wpdir/internal/metrics"
 	"github.com/wpdirectory/wpdir/internal/server"
 	"github.com/wpdirectory/wpdir/internal/tasks"
+	"github.com/wpdirectory/wpdir/internal/webhook"
 )
 
 //go:generate go run -tags=dev embed_files.go
@@ -62,6 +63,9 @@ func main() {
 	db.Setup(c.WD)
 	defer db.Close()
 
+	// Start delivering Webhooks
+	webhook.Setup(c.Webhooks)
+
 	// Setup server struct to hold all App data
 	s := server.New(l, c, flagFresh)
 
@@ -74,6 +78,9 @@ func main() {
 	// Clean up temp dir
 	tasks.Add("0 */15 * * * *", emptyTempDir)
 
+	// Re-run saved Watches which are due
+	tasks.Add("0 * * * * *", s.Manager.RunWatches)
+
 	// Start Task Runner
 	tasks.Start()
 
@@ -113,11 +120,9 @@ func mkdirs(wd string) {
 	ssl := filepath.Join(wd, "data", "ssl")
 	os.MkdirAll(ssl, 0760)
 
-	plugins := filepath.Join(wd, "data", "index", "plugins")
-	os.MkdirAll(plugins, 0766)
-
-	themes := filepath.Join(wd, "data", "index", "themes")
-	os.MkdirAll(themes, 0766)
+	// Each Repo creates its own dir within the index dir
+	index := filepath.Join(wd, "data", "index")
+	os.MkdirAll(index, 0766)
 }
 
 // setTempDir sets the temp dir